@@ -3,6 +3,7 @@ package tinywasm
 import (
 	"os"
 	"path"
+	"slices"
 
 	. "github.com/cdvelop/tinystring"
 )
@@ -36,9 +37,15 @@ func (w *TinyWasm) Change(newValue string, progress chan<- string) {
 			progress <- w.handleTinyGoMissing().Error()
 			return
 		}
+		if err := w.checkMinTinyGoVersion(); err != nil {
+			progress <- err.Error()
+			return
+		}
+		w.warnUnsupportedTinyGoFlags(newValue)
 	}
 
 	// Update active builder
+	previousMode := w.Value()
 	w.updateCurrentBuilder(newValue)
 
 	// Check if main WASM file exists
@@ -59,16 +66,45 @@ func (w *TinyWasm) Change(newValue string, progress chan<- string) {
 		return
 	}
 
-	// Ensure wasm_exec.js is available
-	w.wasmProjectWriteOrReplaceWasmExecJsOutput()
+	// Extra entry points share the runtime of the mode
+	if err := w.compileEntries(newValue); err != nil {
+		progress <- Translate("Warning:", "auto", "compilation", "failed:", err).String()
+		return
+	}
+
+	// Ensure wasm_exec.js is available. When the compiler runtime is unchanged
+	// (e.g. Medium <-> Small) only the mode header differs, so rewrite just that line.
+	if !w.sameWasmExecJsRuntime(previousMode, newValue) || !w.updateWasmExecJsHeader(newValue) {
+		w.wasmProjectWriteOrReplaceWasmExecJsOutput()
+	}
 
 	// Report success
 	progress <- w.getSuccessMessage(newValue)
 }
 
+// ModeSwitchWillRecompile reports whether Change(newMode) would run a build, so a UI
+// can warn before an expensive switch (eg: a TinyGo build of several seconds). It is
+// false for an invalid or the current mode, when the main file does not exist yet (only
+// the mode changes) and when newMode resolves to the same compiler invocation.
+func (w *TinyWasm) ModeSwitchWillRecompile(newMode string) bool {
+	newMode = Convert(newMode).ToUpper().String()
+	if w.validateMode(newMode) != nil || newMode == w.Value() {
+		return false
+	}
+
+	mainWasmPath := path.Join(w.AppRootDir, w.Config.SourceDir, w.Config.MainInputFile)
+	if _, err := os.Stat(mainWasmPath); err != nil {
+		return false
+	}
+
+	current, next := w.builderForMode(w.Value()), w.builderForMode(newMode)
+	return w.compilerCommand(w.Value()) != w.compilerCommand(newMode) ||
+		!slices.Equal(current.BuildArguments(), next.BuildArguments())
+}
+
 // RecompileMainWasm recompiles the main WASM file if it exists
 func (w *TinyWasm) RecompileMainWasm() error {
-	if w.activeBuilder == nil {
+	if w.currentBuilder() == nil {
 		return Err("builder not initialized")
 	}
 	sourceDir := path.Join(w.AppRootDir, w.Config.SourceDir)
@@ -80,7 +116,7 @@ func (w *TinyWasm) RecompileMainWasm() error {
 	}
 
 	// Use gobuild to compile
-	return w.activeBuilder.CompileProgram()
+	return w.compileWith(w.currentBuilder(), w.Value())
 }
 
 // validateMode validates if the provided mode is supported
@@ -0,0 +1,126 @@
+package tinywasm
+
+import (
+	"time"
+
+	. "github.com/cdvelop/tinystring"
+)
+
+// AutoModeController switches the compilation mode from the number of connected
+// clients, eg: build Large while coding alone and Small once external viewers connect.
+// Modes are switched through Change (see ApplyAutoMode), so the usual recompilation applies.
+type AutoModeController struct {
+	ClientCount func() int    // required: returns the number of connected clients
+	Threshold   int           // client count from which HighMode is used (default 1)
+	LowMode     string        // mode below Threshold (default BuildLargeSizeShortcut)
+	HighMode    string        // mode at or above Threshold (default BuildSmallSizeShortcut)
+	Interval    time.Duration // how often ClientCount is polled (default 2s)
+	Debounce    time.Duration // how long a new target mode must hold before switching (default 5s)
+
+	pendingMode  string    // target mode waiting for the debounce period
+	pendingSince time.Time // when pendingMode was first observed
+}
+
+// StartAutoMode starts polling Config.AutoModeController and returns the channel of mode
+// switches to apply and a function that stops the polling (the channel is then closed).
+// Switches are sent rather than applied so the mode and active builder only change on
+// the caller's goroutine, like every other build: drain the channel there, eg:
+//
+//	for mode := range switches {
+//		w.ApplyAutoMode(mode)
+//	}
+func (w *TinyWasm) StartAutoMode() (switches <-chan string, stop func(), err error) {
+	c := w.Config.AutoModeController
+	if c == nil || c.ClientCount == nil {
+		return nil, nil, Err("AutoModeController with a ClientCount provider is required")
+	}
+
+	interval := c.Interval
+	if interval <= 0 {
+		interval = 2 * time.Second
+	}
+
+	modes := make(chan string, 1)
+	done := make(chan struct{})
+	current := w.Value() // tracked here from then on: the polling goroutine never reads w's mode
+	go func() {
+		defer close(modes)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case now := <-ticker.C:
+				mode, ok := c.next(w, current, c.ClientCount(), now)
+				if !ok {
+					continue
+				}
+				select {
+				case modes <- mode:
+					current = mode
+				case <-done:
+					return
+				}
+			}
+		}
+	}()
+
+	return modes, func() { close(done) }, nil
+}
+
+// ApplyAutoMode changes to a mode received from StartAutoMode through Change and logs
+// its outcome. Nothing is done when mode is already active, eg: after a manual switch.
+func (w *TinyWasm) ApplyAutoMode(mode string) {
+	if mode == w.Value() {
+		return
+	}
+	progress := make(chan string, 1) // Change reports exactly one message
+	w.Change(mode, progress)
+	w.Logger("Auto mode:", <-progress)
+}
+
+// next returns the mode to switch to for the given client count, once the target
+// mode differs from the current one and has held for the debounce period
+func (c *AutoModeController) next(w *TinyWasm, current string, clients int, now time.Time) (string, bool) {
+	target := c.targetMode(w, clients)
+	if target == current {
+		c.pendingMode = ""
+		return "", false
+	}
+
+	if target != c.pendingMode {
+		c.pendingMode = target
+		c.pendingSince = now
+	}
+
+	debounce := c.Debounce
+	if debounce <= 0 {
+		debounce = 5 * time.Second
+	}
+	if now.Sub(c.pendingSince) < debounce {
+		return "", false
+	}
+
+	c.pendingMode = ""
+	return target, true
+}
+
+// targetMode returns the mode matching the client count
+func (c *AutoModeController) targetMode(w *TinyWasm, clients int) string {
+	threshold := c.Threshold
+	if threshold <= 0 {
+		threshold = 1
+	}
+
+	if clients >= threshold {
+		if c.HighMode != "" {
+			return c.HighMode
+		}
+		return w.Config.BuildSmallSizeShortcut
+	}
+	if c.LowMode != "" {
+		return c.LowMode
+	}
+	return w.Config.BuildLargeSizeShortcut
+}
@@ -0,0 +1,82 @@
+package tinywasm
+
+import (
+	"testing"
+	"time"
+)
+
+func TestAutoModeControllerDebounce(t *testing.T) {
+	w := New(&Config{AppRootDir: t.TempDir(), Logger: func(...any) {}})
+	c := &AutoModeController{Debounce: 5 * time.Second}
+	start := time.Now()
+
+	if _, ok := c.next(w, w.Value(), 0, start); ok {
+		t.Fatal("no switch expected while no clients are connected in Large mode")
+	}
+
+	// A viewer connects: Small is only chosen once the count holds for the debounce period
+	if _, ok := c.next(w, w.Value(), 2, start.Add(time.Second)); ok {
+		t.Fatal("switch should be debounced")
+	}
+	// The viewer leaves before the period ends: pending switch is dropped
+	if _, ok := c.next(w, w.Value(), 0, start.Add(2*time.Second)); ok {
+		t.Fatal("no switch expected after the viewer left")
+	}
+	if _, ok := c.next(w, w.Value(), 1, start.Add(3*time.Second)); ok {
+		t.Fatal("debounce should restart after thrashing")
+	}
+
+	mode, ok := c.next(w, w.Value(), 1, start.Add(9*time.Second))
+	if !ok || mode != w.Config.BuildSmallSizeShortcut {
+		t.Errorf("next() = %q, %v; want %q after debounce", mode, ok, w.Config.BuildSmallSizeShortcut)
+	}
+}
+
+func TestStartAutoModeRequiresProvider(t *testing.T) {
+	w := New(&Config{AppRootDir: t.TempDir(), Logger: func(...any) {}})
+	if _, _, err := w.StartAutoMode(); err == nil {
+		t.Error("expected error without AutoModeController")
+	}
+
+	w.Config.AutoModeController = &AutoModeController{ClientCount: func() int { return 0 }, Interval: time.Millisecond}
+	switches, stop, err := w.StartAutoMode()
+	if err != nil {
+		t.Fatalf("StartAutoMode failed: %v", err)
+	}
+	time.Sleep(5 * time.Millisecond)
+	stop()
+
+	for mode := range switches {
+		t.Errorf("switch to %s sent with no clients connected", mode)
+	}
+	if w.Value() != w.Config.BuildLargeSizeShortcut {
+		t.Errorf("mode changed to %s with no clients connected", w.Value())
+	}
+}
+
+func TestStartAutoModeSendsSwitches(t *testing.T) {
+	w := New(&Config{AppRootDir: t.TempDir(), Logger: func(...any) {}})
+	w.Config.AutoModeController = &AutoModeController{
+		ClientCount: func() int { return 1 },
+		Interval:    time.Millisecond,
+		Debounce:    time.Millisecond,
+	}
+
+	switches, stop, err := w.StartAutoMode()
+	if err != nil {
+		t.Fatalf("StartAutoMode failed: %v", err)
+	}
+	defer stop()
+
+	select {
+	case mode := <-switches:
+		if mode != w.Config.BuildSmallSizeShortcut {
+			t.Errorf("switch to %s, want %s", mode, w.Config.BuildSmallSizeShortcut)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("no switch sent once a client connected")
+	}
+	if w.Value() != w.Config.BuildLargeSizeShortcut {
+		t.Error("StartAutoMode must leave applying the switch to the caller")
+	}
+}
@@ -0,0 +1,75 @@
+package tinywasm
+
+import (
+	"os"
+	"time"
+
+	"github.com/cdvelop/gobuild"
+)
+
+// sourceFingerprint identifies the state of the source file that triggered a build and
+// of the output that build produced
+type sourceFingerprint struct {
+	path          string
+	modTime       time.Time
+	size          int64
+	outputModTime time.Time // zero until the build succeeded
+}
+
+// unchangedSinceLastBuild reports whether filePath has the same modification time and
+// size as when it triggered the last successful build of mode and that build's output
+// is still on disk untouched (a build of another mode sharing the file rewrites it).
+// Otherwise the fingerprint is kept for the build about to run. Always false with
+// Config.ForceRecompile.
+func (w *TinyWasm) unchangedSinceLastBuild(mode, filePath string) bool {
+	if w.Config.ForceRecompile {
+		return false
+	}
+	info, err := os.Stat(filePath)
+	if err != nil {
+		return false
+	}
+	current := sourceFingerprint{path: filePath, modTime: info.ModTime(), size: info.Size()}
+
+	w.buildMu.Lock()
+	defer w.buildMu.Unlock()
+
+	if last, ok := w.fingerprints[mode]; ok && last.path == current.path && last.modTime.Equal(current.modTime) && last.size == current.size {
+		if output, err := os.Stat(w.outputPathFor(w.builderForMode(mode))); err == nil && output.ModTime().Equal(last.outputModTime) {
+			return true
+		}
+	}
+
+	if w.pendingFingerprints == nil {
+		w.pendingFingerprints = make(map[string]sourceFingerprint)
+	}
+	w.pendingFingerprints[mode] = current
+	return false
+}
+
+// recordFingerprint stores the fingerprint of the source that triggered the finished
+// build of mode on b, or forgets it when the build failed
+func (w *TinyWasm) recordFingerprint(b *gobuild.GoBuild, mode string, err error) {
+	var outputModTime time.Time
+	if err == nil {
+		if info, statErr := os.Stat(w.outputPathFor(b)); statErr == nil {
+			outputModTime = info.ModTime()
+		}
+	}
+
+	w.buildMu.Lock()
+	defer w.buildMu.Unlock()
+
+	pending, ok := w.pendingFingerprints[mode]
+	delete(w.pendingFingerprints, mode)
+	if err != nil || !ok || outputModTime.IsZero() {
+		delete(w.fingerprints, mode)
+		return
+	}
+
+	if w.fingerprints == nil {
+		w.fingerprints = make(map[string]sourceFingerprint)
+	}
+	pending.outputModTime = outputModTime
+	w.fingerprints[mode] = pending
+}
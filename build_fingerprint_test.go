@@ -0,0 +1,69 @@
+package tinywasm
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNewFileEventSkipsUnchangedSource(t *testing.T) {
+	tmp := t.TempDir()
+	writeWasmProject(t, tmp)
+
+	var logs []string
+	w := New(&Config{
+		AppRootDir:              tmp,
+		SourceDir:               "web",
+		OutputDir:               "web/public",
+		MainInputFile:           "main.go",
+		DisableWasmExecJsOutput: true,
+		Logger: func(message ...any) {
+			logs = append(logs, fmt.Sprint(message...))
+		},
+	})
+
+	mainPath := filepath.Join(tmp, "web", "main.go")
+	skipped := func() bool {
+		logs = nil
+		if err := w.NewFileEvent("main.go", ".go", mainPath, "write"); err != nil {
+			t.Fatalf("NewFileEvent failed: %v", err)
+		}
+		for _, l := range logs {
+			if strings.Contains(l, "Skipping compilation") {
+				return true
+			}
+		}
+		return false
+	}
+
+	if skipped() {
+		t.Fatal("first event must compile")
+	}
+	if !skipped() {
+		t.Error("duplicate event for an unchanged file should be skipped")
+	}
+
+	w.Config.ForceRecompile = true
+	if skipped() {
+		t.Error("ForceRecompile should disable skipping")
+	}
+	w.Config.ForceRecompile = false
+
+	later := time.Now().Add(time.Second)
+	if err := os.Chtimes(mainPath, later, later); err != nil {
+		t.Fatal(err)
+	}
+	if skipped() {
+		t.Error("a modified file must compile")
+	}
+
+	if err := os.Remove(filepath.Join(tmp, "web", "public", "main.wasm")); err != nil {
+		t.Fatal(err)
+	}
+	if skipped() {
+		t.Error("a missing output must compile")
+	}
+}
@@ -0,0 +1,69 @@
+package tinywasm
+
+import (
+	"slices"
+	"time"
+)
+
+// defaultHistorySize is the number of builds kept when Config.HistorySize is not set
+const defaultHistorySize = 20
+
+// BuildResult describes a finished build
+type BuildResult struct {
+	Mode       string        // mode shortcut eg: "L"
+	Duration   time.Duration // compile time including post-build steps
+	Size       int64         // wasm output size in bytes, 0 when the build failed
+	Err        error         // nil on success
+	FinishedAt time.Time
+}
+
+// buildHistory is a bounded ring buffer of build results, guarded by TinyWasm.buildMu
+type buildHistory struct {
+	results []BuildResult // ring storage
+	start   int           // index of the oldest result once the ring is full
+}
+
+// add stores result, overwriting the oldest entry once size results are kept
+func (h *buildHistory) add(result BuildResult, size int) {
+	if size <= 0 {
+		size = defaultHistorySize
+	}
+
+	// HistorySize changed after the ring filled up: keep the newest entries in order
+	if len(h.results) > size || (h.start != 0 && len(h.results) < size) {
+		ordered := h.ordered()
+		h.results = ordered[max(0, len(ordered)-size):]
+		h.start = 0
+	}
+
+	if len(h.results) < size {
+		h.results = append(h.results, result)
+		return
+	}
+	h.results[h.start] = result
+	h.start = (h.start + 1) % size
+}
+
+// ordered returns the kept results from oldest to newest
+func (h *buildHistory) ordered() []BuildResult {
+	return append(slices.Clone(h.results[h.start:]), h.results[:h.start]...)
+}
+
+// recent returns up to n results, newest first (all results when n <= 0)
+func (h *buildHistory) recent(n int) []BuildResult {
+	out := h.ordered()
+	slices.Reverse(out)
+	if n > 0 && n < len(out) {
+		out = out[:n]
+	}
+	return out
+}
+
+// BuildHistory returns up to the n most recent build results, newest first
+// (every kept result when n <= 0). Config.HistorySize bounds how many are kept.
+// Safe to call while builds are running.
+func (w *TinyWasm) BuildHistory(n int) []BuildResult {
+	w.buildMu.Lock()
+	defer w.buildMu.Unlock()
+	return w.history.recent(n)
+}
@@ -0,0 +1,68 @@
+package tinywasm
+
+import "testing"
+
+func TestBuildHistoryRingBuffer(t *testing.T) {
+	var h buildHistory
+	for i := 1; i <= 5; i++ {
+		h.add(BuildResult{Size: int64(i)}, 3)
+	}
+
+	sizes := func(results []BuildResult) []int64 {
+		var out []int64
+		for _, r := range results {
+			out = append(out, r.Size)
+		}
+		return out
+	}
+
+	if got := sizes(h.recent(0)); len(got) != 3 || got[0] != 5 || got[1] != 4 || got[2] != 3 {
+		t.Errorf("recent(0) = %v, want [5 4 3]", got)
+	}
+	if got := sizes(h.recent(2)); len(got) != 2 || got[0] != 5 || got[1] != 4 {
+		t.Errorf("recent(2) = %v, want [5 4]", got)
+	}
+
+	// Growing the history keeps order
+	h.add(BuildResult{Size: 6}, 4)
+	h.add(BuildResult{Size: 7}, 4)
+	if got := sizes(h.recent(0)); len(got) != 4 || got[0] != 7 || got[3] != 4 {
+		t.Errorf("after resize recent(0) = %v, want [7 6 5 4]", got)
+	}
+}
+
+func TestBuildHistoryRecordsBuilds(t *testing.T) {
+	tmp := t.TempDir()
+	writeWasmProject(t, tmp)
+
+	w := New(&Config{
+		AppRootDir:              tmp,
+		SourceDir:               "web",
+		OutputDir:               "web/public",
+		MainInputFile:           "main.go",
+		DisableWasmExecJsOutput: true,
+		Logger:                  func(...any) {},
+	})
+
+	if err := w.RecompileMainWasm(); err != nil {
+		t.Fatalf("RecompileMainWasm failed: %v", err)
+	}
+
+	w.Config.RequiredExports = []string{"missing"}
+	if err := w.RecompileMainWasm(); err == nil {
+		t.Fatal("expected build to fail on missing export")
+	}
+
+	history := w.BuildHistory(10)
+	if len(history) != 2 {
+		t.Fatalf("BuildHistory(10) returned %d results, want 2", len(history))
+	}
+
+	failed, ok := history[0], history[1]
+	if failed.Err == nil || failed.Size != 0 {
+		t.Errorf("newest result should be the failed build, got %+v", failed)
+	}
+	if ok.Err != nil || ok.Size == 0 || ok.Duration <= 0 || ok.Mode != w.Config.BuildLargeSizeShortcut {
+		t.Errorf("unexpected successful build result %+v", ok)
+	}
+}
@@ -0,0 +1,82 @@
+package tinywasm
+
+import (
+	"sync"
+
+	"github.com/cdvelop/gobuild"
+)
+
+// outputLock returns the lock serializing the builds (compile and post-build steps) that
+// write outputPath, so two builds never rewrite the same wasm and its copies at once
+func (w *TinyWasm) outputLock(outputPath string) *sync.Mutex {
+	w.buildMu.Lock()
+	defer w.buildMu.Unlock()
+	if w.outputLocks == nil {
+		w.outputLocks = make(map[string]*sync.Mutex)
+	}
+	lock, ok := w.outputLocks[outputPath]
+	if !ok {
+		lock = &sync.Mutex{}
+		w.outputLocks[outputPath] = lock
+	}
+	return lock
+}
+
+// acquireBuilder takes the output lock of b for a new build. A build of b still running
+// is cancelled first and its post-build steps are awaited: the newest request wins
+// (cancel-and-restart) and builds of the same output never overlap.
+func (w *TinyWasm) acquireBuilder(b *gobuild.GoBuild) *sync.Mutex {
+	lock := w.outputLock(b.FinalOutputPath())
+	if !lock.TryLock() {
+		w.Logger("Build of", b.MainOutputFileNameWithExtension(), "in progress, restarting it")
+		b.Cancel()
+		lock.Lock()
+	}
+	return lock
+}
+
+// asyncBuild is the build in flight on a builder compiling asynchronously (Config.Callback):
+// compileWith records the output lock it took and the builder callback releases that lock
+type asyncBuild struct {
+	builder *gobuild.GoBuild // set once created, the callback never looks the builder up again
+	lock    *sync.Mutex      // output lock taken by compileWith for the running build
+}
+
+// newBuilder creates the builder of config for mode. When Config.Callback is set its
+// callback finishes the build compileWith started on that same builder (see asyncCallback).
+func (w *TinyWasm) newBuilder(config *gobuild.Config, mode string) *gobuild.GoBuild {
+	if w.Callback == nil {
+		return gobuild.New(config)
+	}
+	build := &asyncBuild{}
+	config.Callback = w.asyncCallback(mode, build)
+	build.builder = gobuild.New(config)
+
+	w.buildMu.Lock()
+	if w.asyncBuilds == nil {
+		w.asyncBuilds = make(map[*gobuild.GoBuild]*asyncBuild)
+	}
+	w.asyncBuilds[build.builder] = build
+	w.buildMu.Unlock()
+	return build.builder
+}
+
+// asyncBuildOf returns the async build state of b, nil when b compiles synchronously
+func (w *TinyWasm) asyncBuildOf(b *gobuild.GoBuild) *asyncBuild {
+	w.buildMu.Lock()
+	defer w.buildMu.Unlock()
+	return w.asyncBuilds[b]
+}
+
+// cancelBuilds cancels the builds running on builders and waits for their post-build
+// steps to release the output locks, so none of them still reads the state being reset
+func (w *TinyWasm) cancelBuilds(builders []*gobuild.GoBuild) {
+	for _, b := range builders {
+		b.Cancel()
+	}
+	for _, b := range builders {
+		lock := w.outputLock(b.FinalOutputPath())
+		lock.Lock()
+		lock.Unlock()
+	}
+}
@@ -0,0 +1,61 @@
+package tinywasm
+
+import "slices"
+
+// buildQueue coalesces compile requests arriving while a build runs, guarded by
+// TinyWasm.buildMu. Requests are keyed by mode: any number of requests for a mode
+// that is already pending collapse into that single pending build.
+type buildQueue struct {
+	running bool     // a queued build is in progress
+	pending []string // modes waiting to be built, in request order
+}
+
+// queueBuild builds mode through the coalescing queue when Config.MaxPendingBuilds is
+// set, otherwise it builds right away. When a build is already running the request is
+// recorded as pending (at most once per mode, at most MaxPendingBuilds modes: the oldest
+// pending request is dropped for the newest, which reflects the latest changes) and nil
+// is returned: the running caller builds the pending modes once its build ends and
+// returns the error of the last one.
+//
+// With Config.Callback set compileWith returns once the build has started, so requests
+// are never held back here: they cancel and restart the running build instead (see
+// acquireBuilder), which already keeps a single build per output.
+func (w *TinyWasm) queueBuild(mode string) error {
+	if w.Config.MaxPendingBuilds <= 0 {
+		return w.compileWith(w.builderForMode(mode), mode)
+	}
+
+	w.buildMu.Lock()
+	if w.queue.running {
+		switch {
+		case slices.Contains(w.queue.pending, mode):
+			w.buildMu.Unlock()
+			w.Logger("Build of mode", mode, "already pending, request coalesced")
+		case len(w.queue.pending) >= w.Config.MaxPendingBuilds:
+			dropped := w.queue.pending[0]
+			w.queue.pending = append(w.queue.pending[1:], mode)
+			w.buildMu.Unlock()
+			w.Logger("Build queue full, dropping oldest build request for mode", dropped)
+		default:
+			w.queue.pending = append(w.queue.pending, mode)
+			w.buildMu.Unlock()
+		}
+		return nil
+	}
+	w.queue.running = true
+	w.buildMu.Unlock()
+
+	for {
+		err := w.compileWith(w.builderForMode(mode), mode)
+
+		w.buildMu.Lock()
+		if len(w.queue.pending) == 0 {
+			w.queue.running = false
+			w.buildMu.Unlock()
+			return err
+		}
+		mode = w.queue.pending[0]
+		w.queue.pending = w.queue.pending[1:]
+		w.buildMu.Unlock()
+	}
+}
@@ -0,0 +1,68 @@
+package tinywasm
+
+import (
+	"os/exec"
+	"slices"
+	"testing"
+)
+
+// TestBuildQueueCoalescesRequests verifies requests arriving during a build collapse into one pending build
+func TestBuildQueueCoalescesRequests(t *testing.T) {
+	if _, err := exec.LookPath("go"); err != nil {
+		t.Skip("go not found in PATH")
+	}
+
+	tmp := t.TempDir()
+	writeWasmProject(t, tmp)
+
+	w := New(&Config{
+		AppRootDir:              tmp,
+		SourceDir:               "web",
+		OutputDir:               "web/public",
+		MainInputFile:           "main.go",
+		MaxPendingBuilds:        1,
+		DisableWasmExecJsOutput: true,
+		Logger:                  func(...any) {},
+	})
+
+	// Simulate a burst of events while a build is running
+	w.queue.running = true
+	for range 5 {
+		if err := w.queueBuild("L"); err != nil {
+			t.Fatalf("queued request returned %v", err)
+		}
+	}
+	if !slices.Equal(w.queue.pending, []string{"L"}) {
+		t.Fatalf("pending = %v, want a single coalesced L build", w.queue.pending)
+	}
+
+	// Queue full: the oldest pending request gives way to the newest
+	if err := w.queueBuild("S"); err != nil {
+		t.Fatalf("queued request returned %v", err)
+	}
+	if !slices.Equal(w.queue.pending, []string{"S"}) {
+		t.Fatalf("pending = %v, want S replacing the older L request", w.queue.pending)
+	}
+	if err := w.queueBuild("L"); err != nil {
+		t.Fatalf("queued request returned %v", err)
+	}
+	if !slices.Equal(w.queue.pending, []string{"L"}) {
+		t.Fatalf("pending = %v, want L replacing the older S request", w.queue.pending)
+	}
+	if len(w.BuildHistory(0)) != 0 {
+		t.Fatal("queued requests must not build while another build runs")
+	}
+
+	// The running caller builds its own request and then the pending one
+	w.queue.running = false
+	if err := w.queueBuild("L"); err != nil {
+		t.Fatalf("queueBuild failed: %v", err)
+	}
+
+	if history := w.BuildHistory(0); len(history) != 2 {
+		t.Errorf("expected 2 builds (request plus coalesced pending), got %d", len(history))
+	}
+	if w.queue.running || len(w.queue.pending) != 0 {
+		t.Errorf("queue should be idle, got running=%v pending=%v", w.queue.running, w.queue.pending)
+	}
+}
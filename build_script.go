@@ -0,0 +1,100 @@
+package tinywasm
+
+import (
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/cdvelop/gobuild"
+	. "github.com/cdvelop/tinystring"
+)
+
+// ExportBuildScript returns a POSIX shell script running the exact build TinyWasm
+// performs for mode: same working directory, environment, compiler and arguments,
+// writing straight to the final output instead of a temp name, followed by the
+// post-build steps that have a command line equivalent (permissions, gzip, brotli).
+// Useful to reproduce a build without this package and to tell package issues
+// from toolchain issues.
+func (w *TinyWasm) ExportBuildScript(mode string) (string, error) {
+	if err := w.validateMode(mode); err != nil {
+		return "", err
+	}
+	mode = Convert(mode).ToUpper().String()
+
+	config, args, output := w.buildCommand(mode)
+
+	var sb strings.Builder
+	sb.WriteString("#!/bin/sh\n")
+	sb.WriteString("# TinyWasm build, mode " + mode + " (" + config.Command + ")\n")
+	sb.WriteString("set -e\n\n")
+	sb.WriteString("cd " + shellQuote(config.OutFolderRelativePath) + "\n")
+	for _, env := range config.Env {
+		name, value, _ := strings.Cut(env, "=")
+		sb.WriteString("export " + name + "=" + shellQuote(value) + "\n")
+	}
+
+	quoted := make([]string, 0, len(args)+1)
+	quoted = append(quoted, shellQuote(config.Command))
+	for _, arg := range args {
+		quoted = append(quoted, shellQuote(arg))
+	}
+	sb.WriteString(strings.Join(quoted, " ") + "\n")
+
+	name := shellQuote(filepath.Base(output))
+	if w.Config.OutputFilePerm != 0 {
+		sb.WriteString("chmod " + strconv.FormatUint(uint64(w.Config.OutputFilePerm.Perm()), 8) + " " + name + "\n")
+	}
+	if w.Config.EmitGzip {
+		sb.WriteString("gzip -c -" + strconv.Itoa(w.gzipLevel()) + " " + name + " > " + shellQuote(filepath.Base(output)+gzipExtension) + "\n")
+	}
+	if w.Config.EmitBrotli {
+		sb.WriteString("brotli -f -q " + strconv.Itoa(w.brotliQuality()) + " -o " + shellQuote(filepath.Base(output)+brotliExtension) + " " + name + "\n")
+	}
+	if w.Config.EmitSymbols && w.requiresTinyGo(mode) {
+		sb.WriteString("# " + filepath.Base(output) + symbolsFileExtension + " is extracted from the wasm name section by TinyWasm itself\n")
+	}
+
+	return sb.String(), nil
+}
+
+// CompileCommandPreview returns the command line the next build of the active mode
+// would run, environment included, eg: "GOOS=js GOARCH=wasm go build -tags dev -o
+// /app/web/public/main.wasm /app/web/main.go". Nothing is compiled. Builds run from
+// OutputDir and write to a temp name first; the final output path is shown instead.
+func (w *TinyWasm) CompileCommandPreview() string {
+	config, args, _ := w.buildCommand(w.Value())
+
+	parts := make([]string, 0, len(config.Env)+len(args)+1)
+	for _, env := range config.Env {
+		name, value, _ := strings.Cut(env, "=")
+		parts = append(parts, name+"="+shellQuote(value))
+	}
+	parts = append(parts, shellQuote(config.Command))
+	for _, arg := range args {
+		parts = append(parts, shellQuote(arg))
+	}
+	return strings.Join(parts, " ")
+}
+
+// buildCommand returns the builder configuration of mode with its resolved compiler
+// arguments, writing straight to the final output path (also returned)
+func (w *TinyWasm) buildCommand(mode string) (*gobuild.Config, []string, string) {
+	config := w.builderConfig(mode)
+	b := w.builderForMode(mode)
+	output := b.FinalOutputPath()
+
+	args := b.BuildArguments()
+	if i := slices.Index(args, "-o"); i >= 0 && i+1 < len(args) {
+		args[i+1] = output
+	}
+	return config, args, output
+}
+
+// shellQuote quotes s for a POSIX shell when it contains anything but safe characters
+func shellQuote(s string) string {
+	if s != "" && strings.Trim(s, "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789-_=.,/:@+%") == "" {
+		return s
+	}
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
@@ -0,0 +1,98 @@
+package tinywasm
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestExportBuildScript(t *testing.T) {
+	tmp := t.TempDir()
+	w := New(&Config{
+		AppRootDir:              tmp,
+		SourceDir:               "web",
+		OutputDir:               "web/public",
+		MainInputFile:           "main.go",
+		EmitGzip:                true,
+		OutputFilePerm:          0640,
+		DisableWasmExecJsOutput: true,
+		Logger:                  func(...any) {},
+	})
+
+	if _, err := w.ExportBuildScript("X"); err == nil {
+		t.Error("expected an error for an invalid mode")
+	}
+
+	script, err := w.ExportBuildScript("l")
+	if err != nil {
+		t.Fatalf("ExportBuildScript failed: %v", err)
+	}
+
+	output := filepath.Join(tmp, "web", "public", "main.wasm")
+	for _, expected := range []string{
+		"cd " + filepath.Join(tmp, "web", "public") + "\n",
+		"export GOOS=js\nexport GOARCH=wasm\n",
+		"go build -tags dev -o " + output + " " + filepath.Join(tmp, "web", "main.go") + "\n",
+		"chmod 640 main.wasm\n",
+		"gzip -c -9 main.wasm > main.wasm.gz\n",
+	} {
+		if !strings.Contains(script, expected) {
+			t.Errorf("script missing %q:\n%s", expected, script)
+		}
+	}
+
+	if got := shellQuote("it's here"); got != `'it'\''s here'` {
+		t.Errorf("shellQuote = %s", got)
+	}
+}
+
+// TestExportBuildScriptRuns verifies the exported script reproduces the Large build
+func TestExportBuildScriptRuns(t *testing.T) {
+	if _, err := exec.LookPath("go"); err != nil {
+		t.Skip("go not found in PATH")
+	}
+
+	tmp := t.TempDir()
+	writeWasmProject(t, tmp)
+	w := New(&Config{
+		AppRootDir:              tmp,
+		SourceDir:               "web",
+		OutputDir:               "web/public",
+		MainInputFile:           "main.go",
+		DisableWasmExecJsOutput: true,
+		Logger:                  func(...any) {},
+	})
+
+	script, err := w.ExportBuildScript("L")
+	if err != nil {
+		t.Fatalf("ExportBuildScript failed: %v", err)
+	}
+	if out, err := exec.Command("sh", "-c", script).CombinedOutput(); err != nil {
+		t.Fatalf("script failed: %v\n%s", err, out)
+	}
+	if _, err := os.Stat(filepath.Join(tmp, "web", "public", "main.wasm")); err != nil {
+		t.Errorf("script did not produce main.wasm: %v", err)
+	}
+}
+
+// TestCompileCommandPreview verifies the preview of the active mode's command line
+func TestCompileCommandPreview(t *testing.T) {
+	w := New(&Config{
+		AppRootDir:              "/project",
+		SourceDir:               "web",
+		OutputDir:               "web/public",
+		MainInputFile:           "main.go",
+		DisableWasmExecJsOutput: true,
+		Logger:                  func(...any) {},
+		CompilingArguments: func() []string {
+			return []string{"-trimpath"}
+		},
+	})
+
+	want := "GOOS=js GOARCH=wasm go build -tags dev -trimpath -o /project/web/public/main.wasm /project/web/main.go"
+	if got := w.CompileCommandPreview(); got != want {
+		t.Errorf("CompileCommandPreview() = %q, want %q", got, want)
+	}
+}
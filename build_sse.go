@@ -0,0 +1,45 @@
+package tinywasm
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+// BuildSSEHandler returns an http.Handler streaming build events (see Events) as
+// server-sent events, one "data: {json}" message per event, so a web dashboard can
+// show live build status with an EventSource instead of polling. Each request holds
+// its own subscription until the client disconnects.
+func (w *TinyWasm) BuildSSEHandler() http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		flusher, ok := rw.(http.Flusher)
+		if !ok {
+			http.Error(rw, "streaming not supported", http.StatusInternalServerError)
+			return
+		}
+
+		events, cancel := w.Events()
+		defer cancel()
+
+		rw.Header().Set("Content-Type", "text/event-stream")
+		rw.Header().Set("Cache-Control", "no-cache")
+		rw.Header().Set("Connection", "keep-alive")
+		rw.WriteHeader(http.StatusOK)
+		flusher.Flush()
+
+		for {
+			select {
+			case <-r.Context().Done():
+				return
+			case e := <-events:
+				data, err := json.Marshal(e)
+				if err != nil {
+					continue
+				}
+				if _, err := rw.Write([]byte("data: " + string(data) + "\n\n")); err != nil {
+					return
+				}
+				flusher.Flush()
+			}
+		}
+	})
+}
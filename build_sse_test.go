@@ -0,0 +1,48 @@
+package tinywasm
+
+import (
+	"bufio"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestBuildSSEHandler(t *testing.T) {
+	w := New(&Config{AppRootDir: t.TempDir(), Logger: func(...any) {}})
+
+	server := httptest.NewServer(w.BuildSSEHandler())
+	defer server.Close()
+
+	resp, err := http.Get(server.URL)
+	if err != nil {
+		t.Fatalf("GET failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if ct := resp.Header.Get("Content-Type"); ct != "text/event-stream" {
+		t.Errorf("Content-Type = %q, want text/event-stream", ct)
+	}
+
+	// The subscription is registered before the headers are flushed
+	w.buildStarted("M")
+
+	lines := make(chan string)
+	go func() {
+		scanner := bufio.NewScanner(resp.Body)
+		for scanner.Scan() {
+			lines <- scanner.Text()
+		}
+		close(lines)
+	}()
+
+	select {
+	case line := <-lines:
+		if !strings.HasPrefix(line, "data: ") || !strings.Contains(line, `"type":"start"`) || !strings.Contains(line, `"mode":"M"`) {
+			t.Errorf("unexpected SSE line %q", line)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for SSE event")
+	}
+}
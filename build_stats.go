@@ -0,0 +1,57 @@
+package tinywasm
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/cdvelop/gobuild"
+)
+
+// buildStatsFileName is the file written in OutputDir when Config.EmitBuildStats is set
+const buildStatsFileName = "build-stats.json"
+
+// BuildStats is the content of the build-stats.json file (see Config.EmitBuildStats)
+type BuildStats struct {
+	Mode       string    `json:"mode"`        // mode shortcut eg: "S"
+	Output     string    `json:"output"`      // wasm file name eg: "main.wasm"
+	Size       int64     `json:"size"`        // wasm size in bytes
+	DurationMs int64     `json:"duration_ms"` // build time including the post-build steps
+	Compiler   string    `json:"compiler"`    // "go" or "tinygo"
+	Args       []string  `json:"args"`        // compiling arguments (see ResolveCompiler)
+	Timestamp  time.Time `json:"timestamp"`   // when the build finished
+}
+
+// BuildStatsPath returns the path of the build-stats.json file in OutputDir
+func (w *TinyWasm) BuildStatsPath() string {
+	return filepath.Join(w.Config.AppRootDir, w.Config.OutputDir, buildStatsFileName)
+}
+
+// writeBuildStats writes build-stats.json for the successful build of mode on b
+func (w *TinyWasm) writeBuildStats(b *gobuild.GoBuild, mode string) {
+	outputPath := w.outputPathFor(b)
+	info, err := os.Stat(outputPath)
+	if err != nil {
+		w.Logger("Warning: could not write build stats:", err)
+		return
+	}
+
+	stats := BuildStats{
+		Mode:       mode,
+		Output:     filepath.Base(outputPath),
+		Size:       info.Size(),
+		DurationMs: w.LastBuildDuration().Milliseconds(),
+		Compiler:   w.compilerCommand(mode),
+		Args:       w.compilingArguments(mode),
+		Timestamp:  time.Now(),
+	}
+
+	data, err := json.MarshalIndent(stats, "", "  ")
+	if err == nil {
+		err = os.WriteFile(w.BuildStatsPath(), append(data, '\n'), w.outputFilePerm())
+	}
+	if err != nil {
+		w.Logger("Warning: could not write build stats:", err)
+	}
+}
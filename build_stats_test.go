@@ -0,0 +1,42 @@
+package tinywasm
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestEmitBuildStats(t *testing.T) {
+	tmp := t.TempDir()
+	writeWasmProject(t, tmp)
+
+	w := New(&Config{
+		AppRootDir:              tmp,
+		SourceDir:               "web",
+		OutputDir:               "web/public",
+		DisableWasmExecJsOutput: true,
+		EmitBuildStats:          true,
+		Logger:                  func(...any) {},
+	})
+	if !slices.Contains(w.UnobservedFiles(), buildStatsFileName) {
+		t.Errorf("UnobservedFiles() = %v, want %s", w.UnobservedFiles(), buildStatsFileName)
+	}
+
+	if err := w.NewFileEvent("main.go", ".go", filepath.Join(tmp, "web", "main.go"), "write"); err != nil {
+		t.Fatalf("build failed: %v", err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(tmp, "web", "public", buildStatsFileName))
+	if err != nil {
+		t.Fatalf("build stats not written: %v", err)
+	}
+	var stats BuildStats
+	if err := json.Unmarshal(data, &stats); err != nil {
+		t.Fatalf("invalid build stats: %v", err)
+	}
+	if stats.Mode != "L" || stats.Output != "main.wasm" || stats.Size != w.LastOutputSize() || stats.Compiler != "go" || stats.Timestamp.IsZero() {
+		t.Errorf("build stats = %+v", stats)
+	}
+}
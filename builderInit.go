@@ -1,92 +1,357 @@
 package tinywasm
 
 import (
+	"os"
 	"path"
 	"path/filepath"
+	"slices"
 	"strings"
 	"time"
 
 	"github.com/cdvelop/gobuild"
+	. "github.com/cdvelop/tinystring"
 )
 
 // builderWasmInit configures 3 builders for WASM compilation modes
 func (w *TinyWasm) builderWasmInit() {
+	w.buildMu.Lock()
+	w.asyncBuilds = nil // builds in flight keep their own state
+	w.buildMu.Unlock()
+
+	// Configure Coding builder (Go standard)
+	w.builderLarge = w.newBuilder(w.builderConfig(w.Config.BuildLargeSizeShortcut), w.Config.BuildLargeSizeShortcut)
+
+	// Configure Debug builder (TinyGo debug-friendly)
+	w.builderMedium = w.newBuilder(w.builderConfig(w.Config.BuildMediumSizeShortcut), w.Config.BuildMediumSizeShortcut)
+
+	// Configure Production builder (TinyGo optimized)
+	w.builderSmall = w.newBuilder(w.builderConfig(w.Config.BuildSmallSizeShortcut), w.Config.BuildSmallSizeShortcut)
+
+	// Set initial mode and active builder (default to coding mode)
+	w.modeMu.Lock()
+	w.activeBuilder = w.builderLarge // Default: fast development
+	w.modeMu.Unlock()
+
+	// Additional programs, eg: a web worker (see Config.ExtraEntryPoints)
+	w.entryBuilderInit()
+}
+
+// builderConfig returns the gobuild configuration used by the builder of the given mode
+func (w *TinyWasm) builderConfig(mode string) *gobuild.Config {
 	sourceDir := path.Join(w.AppRootDir, w.Config.SourceDir)
 	outputDir := path.Join(w.AppRootDir, w.Config.OutputDir)
 	mainInputFileRelativePath := path.Join(sourceDir, w.Config.MainInputFile)
 
-	// Base configuration shared by all builders
-	baseConfig := gobuild.Config{
+	config := &gobuild.Config{
+		Command:                   w.compilerCommand(mode),
 		MainInputFileRelativePath: mainInputFileRelativePath,
-		OutName:                   w.Config.OutputName, // Output will be {OutputName}.wasm
+		OutName:                   w.outName(mode), // Output will be {OutputName}.wasm
 		Extension:                 ".wasm",
 		OutFolderRelativePath:     outputDir,
 		Logger:                    w.Logger,
-		Timeout:                   60 * time.Second, // 1 minute for all modes
-		Callback:                  w.Callback,
+		Timeout:                   w.compileTimeout(mode),
+		CompilingArguments: func() []string {
+			return w.compilingArguments(mode)
+		},
 	}
 
-	// Configure Coding builder (Go standard)
-	codingConfig := baseConfig
-	codingConfig.Command = "go"
-	codingConfig.Env = []string{"GOOS=js", "GOARCH=wasm"}
-	codingConfig.CompilingArguments = func() []string {
-		args := []string{"-tags", "dev"}
-		if w.CompilingArguments != nil {
-			args = append(args, w.CompilingArguments()...)
+	if !w.requiresTinyGo(mode) {
+		config.Env = []string{"GOOS=js", "GOARCH=wasm"}
+		if w.isReactor() {
+			config.Env = []string{"GOOS=wasip1", "GOARCH=wasm"}
+		}
+		if goFlags := w.goFlagsEnv(); goFlags != "" {
+			config.Env = append(config.Env, "GOFLAGS="+goFlags)
 		}
-		return args
 	}
-	w.builderLarge = gobuild.New(&codingConfig)
 
-	// Configure Debug builder (TinyGo debug-friendly)
-	debugConfig := baseConfig
-	debugConfig.Command = "tinygo"
-	debugConfig.CompilingArguments = func() []string {
-		args := []string{"-target", "wasm", "-opt=1"} // Keep debug symbols
-		if w.CompilingArguments != nil {
-			args = append(args, w.CompilingArguments()...)
+	if tempDir := w.tempDir(); tempDir != "" {
+		// TMPDIR for tinygo and the go tool itself, GOTMPDIR for the go command's work dir
+		config.Env = append(config.Env, "TMPDIR="+tempDir, "GOTMPDIR="+tempDir)
+	}
+
+	config.Env = w.withExtraEnv(config.Env, mode)
+
+	return config
+}
+
+// withExtraEnv returns env with Config.ExtraEnv applied: variables already in env are
+// replaced in place and new ones appended. GOOS and GOARCH of Go builds are kept.
+func (w *TinyWasm) withExtraEnv(env []string, mode string) []string {
+	for _, extra := range w.Config.ExtraEnv {
+		name, _, _ := strings.Cut(extra, "=")
+		if !w.requiresTinyGo(mode) && (name == "GOOS" || name == "GOARCH") {
+			continue
+		}
+		i := slices.IndexFunc(env, func(v string) bool { return strings.HasPrefix(v, name+"=") })
+		if i >= 0 {
+			env[i] = extra
+		} else {
+			env = append(env, extra)
 		}
-		return args
 	}
-	w.builderMedium = gobuild.New(&debugConfig)
+	return env
+}
 
-	// Configure Production builder (TinyGo optimized)
-	prodConfig := baseConfig
-	prodConfig.Command = "tinygo"
-	prodConfig.CompilingArguments = func() []string {
-		args := []string{"-target", "wasm", "-opt=z", "-no-debug", "-panic=trap"}
-		if w.CompilingArguments != nil {
-			args = append(args, w.CompilingArguments()...)
+// compileTimeout returns the compilation timeout of mode: the matching Config.Timeout*
+// field, or its default when zero
+func (w *TinyWasm) compileTimeout(mode string) time.Duration {
+	switch mode {
+	case w.Config.BuildMediumSizeShortcut:
+		if w.Config.TimeoutMedium > 0 {
+			return w.Config.TimeoutMedium
 		}
+		return 60 * time.Second
+	case w.Config.BuildSmallSizeShortcut:
+		if w.Config.TimeoutSmall > 0 {
+			return w.Config.TimeoutSmall
+		}
+		return 120 * time.Second
+	default:
+		if w.Config.TimeoutLarge > 0 {
+			return w.Config.TimeoutLarge
+		}
+		return 30 * time.Second
+	}
+}
+
+// buildTags returns the build tags of the given mode (see Config.BuildTagsLarge)
+func (w *TinyWasm) buildTags(mode string) []string {
+	switch mode {
+	case w.Config.BuildMediumSizeShortcut:
+		return w.Config.BuildTagsMedium
+	case w.Config.BuildSmallSizeShortcut:
+		return w.Config.BuildTagsSmall
+	default:
+		if len(w.Config.BuildTagsLarge) > 0 {
+			return w.Config.BuildTagsLarge
+		}
+		return w.codingBuildTags()
+	}
+}
+
+// codingBuildTags returns the build tags of the Large (coding) mode: Config.CodingBuildTags,
+// or "dev" when it was never set (nil). An empty non-nil slice disables the tags.
+func (w *TinyWasm) codingBuildTags() []string {
+	if w.Config.CodingBuildTags == nil {
+		return []string{"dev"}
+	}
+	return w.Config.CodingBuildTags
+}
+
+// goFlagsEnv returns the GOFLAGS for Go builds: the inherited GOFLAGS followed by
+// Config.GoFlags, so the configured flags take precedence without dropping global ones
+func (w *TinyWasm) goFlagsEnv() string {
+	if w.Config.GoFlags == "" {
+		return ""
+	}
+	return strings.TrimSpace(os.Getenv("GOFLAGS") + " " + w.Config.GoFlags)
+}
+
+// tempDir returns Config.TempDir as an absolute path, resolving a relative one against
+// AppRootDir like the other project paths, or "" to use the OS temp dir
+func (w *TinyWasm) tempDir() string {
+	if w.Config.TempDir == "" {
+		return ""
+	}
+	tempDir := w.Config.TempDir
+	if !filepath.IsAbs(tempDir) {
+		tempDir = filepath.Join(w.Config.AppRootDir, tempDir)
+	}
+	if abs, err := filepath.Abs(tempDir); err == nil {
+		return abs
+	}
+	return tempDir
+}
+
+// CompilerArgs returns the fully resolved arguments passed to the compiler of the given
+// mode (see compilerCommand) without running a build: mode defaults, flag funcs,
+// CompilingArguments with their -ldflags merged into Config.LDFlags, output and input paths.
+// Real builds write to a unique temp name instead of the "_temp" output shown here.
+// Returns nil for an unknown mode.
+func (w *TinyWasm) CompilerArgs(mode string) []string {
+	if w.validateMode(mode) != nil {
+		return nil
+	}
+	return w.builderForMode(Convert(mode).ToUpper().String()).BuildArguments()
+}
+
+// ResolveCompiler returns the compiler the next build of the active mode will run and
+// its compiling arguments (mode defaults and Config.CompilingArguments, without output
+// and input paths, see CompilerArgs), eg: "tinygo" and [-target wasm -opt=z ...]
+func (w *TinyWasm) ResolveCompiler() (command string, args []string) {
+	mode := w.Value()
+	return w.compilerCommand(mode), w.compilingArguments(mode)
+}
+
+// outName returns the output file name without extension for the given mode:
+// OutputName, plus Config.DebugOutputSuffix for the Medium (debug) mode
+func (w *TinyWasm) outName(mode string) string {
+	if name := w.Config.OutputNamePerMode[mode]; name != "" {
+		return name
+	}
+	if mode == w.Config.BuildMediumSizeShortcut {
+		return w.Config.OutputName + w.Config.DebugOutputSuffix
+	}
+	return w.Config.OutputName
+}
+
+// compilerCommand returns the compiler executable used by the given mode
+func (w *TinyWasm) compilerCommand(mode string) string {
+	if w.requiresTinyGo(mode) {
+		return "tinygo"
+	}
+	return "go"
+}
+
+// compilingArguments returns the compiler arguments for the given mode:
+// the mode defaults followed by Config.CompilingArguments
+func (w *TinyWasm) compilingArguments(mode string) []string {
+	target := w.tinyGoTarget() // Go reactors get GOOS=wasip1 from the builder env
+
+	var args []string
+	switch mode {
+	case w.Config.BuildMediumSizeShortcut:
+		args = []string{"-target", target, "-opt=1"} // Keep debug symbols
+	case w.Config.BuildSmallSizeShortcut:
+		args = []string{"-target", target, "-opt=z", "-no-debug", "-panic=trap"}
+		if w.Config.EmitSymbols {
+			args = []string{"-target", target, "-opt=z", "-panic=trap"} // Keep the name section for symbols
+		}
+	}
+
+	if tags := w.buildTags(mode); len(tags) > 0 {
+		args = append(args, "-tags", strings.Join(tags, ","))
+	}
+
+	if w.isReactor() {
+		args = append(args, "-buildmode=c-shared") // Library module: exports stay callable
+	}
+
+	// Compiler/assembler flags only exist for the Go toolchain
+	if !w.requiresTinyGo(mode) {
+		args = append(args, toolFlagArgument("-gcflags", w.gcFlags(mode))...)
+		args = append(args, toolFlagArgument("-asmflags", modeFlags(w.Config.AsmFlags, mode))...)
+	}
+
+	if w.CompilingArguments != nil {
+		args = append(args, w.CompilingArguments()...)
+	}
+
+	if w.Config.LDFlags != nil {
+		args = w.withLDFlags(args, mode)
+	}
+	return args
+}
+
+// withLDFlags prepends the Config.LDFlags value to args as a single -ldflags argument,
+// moving into it the -ldflags values of args ("-ldflags=v" or "-ldflags v") and their
+// bare -X values ("-X k=v" or "-X k v"), which gobuild would otherwise pass as another
+// -ldflags: the compiler only keeps the last -ldflags
+func (w *TinyWasm) withLDFlags(args []string, mode string) []string {
+	value := strings.TrimSpace(w.Config.LDFlags())
+	if value == "" {
 		return args
 	}
-	w.builderSmall = gobuild.New(&prodConfig)
+	if w.requiresTinyGo(mode) {
+		w.Logger("Warning: TinyGo supports only a subset of -ldflags, check", value, "for mode", mode)
+	}
 
-	// Set initial mode and active builder (default to coding mode)
-	w.activeBuilder = w.builderLarge // Default: fast development
+	flags := []string{value}
+	rest := make([]string, 0, len(args))
+	for i := 0; i < len(args); i++ {
+		if args[i] == "-X" {
+			if i+1 >= len(args) {
+				continue
+			}
+			definition := args[i+1]
+			i++
+			if !strings.Contains(definition, "=") && i+1 < len(args) {
+				definition += "=" + args[i+1] // "-X k v" form
+				i++
+			}
+			flags = append(flags, "-X "+definition)
+			continue
+		}
+		if strings.HasPrefix(args[i], "-X") {
+			flags = append(flags, "-X "+strings.TrimPrefix(args[i], "-X"))
+			continue
+		}
+
+		name, flag, hasValue := strings.Cut(args[i], "=")
+		if name != "-ldflags" && name != "--ldflags" {
+			rest = append(rest, args[i])
+			continue
+		}
+		if !hasValue && i+1 < len(args) {
+			flag = args[i+1]
+			i++
+		}
+		if flag = strings.TrimSpace(flag); flag != "" {
+			flags = append(flags, flag)
+		}
+	}
+	return append([]string{"-ldflags=" + strings.Join(flags, " ")}, rest...)
+}
+
+// gcFlags returns the -gcflags values for the given Go mode: the fast-compile
+// flags when Config.LargeFastCompile applies, followed by Config.GcFlags
+func (w *TinyWasm) gcFlags(mode string) []string {
+	var flags []string
+	if w.Config.LargeFastCompile && mode == w.Config.BuildLargeSizeShortcut {
+		flags = append(flags, "all=-N", "-l") // no optimizations, no inlining
+	}
+	return append(flags, modeFlags(w.Config.GcFlags, mode)...)
+}
+
+// modeFlags returns the flags flagsFor yields for mode, or nil when flagsFor is not set
+func modeFlags(flagsFor func(mode string) []string, mode string) []string {
+	if flagsFor == nil {
+		return nil
+	}
+	return flagsFor(mode)
+}
+
+// toolFlagArgument builds a single "-flag=values" argument from flags, or nothing when empty
+func toolFlagArgument(flag string, flags []string) []string {
+	if len(flags) == 0 {
+		return nil
+	}
+	return []string{flag + "=" + strings.Join(flags, " ")}
 }
 
 // updateCurrentBuilder sets the activeBuilder based on mode and cancels ongoing operations
 func (w *TinyWasm) updateCurrentBuilder(mode string) {
-	// 1. Cancel any ongoing compilation
-	if w.activeBuilder != nil {
-		w.activeBuilder.Cancel()
+	w.modeMu.Lock()
+	previous := w.activeBuilder
+	w.currentMode = mode
+	w.activeBuilder = w.builderForMode(mode)
+	w.modeMu.Unlock()
+
+	// Cancel any ongoing compilation of the previous mode
+	if previous != nil {
+		previous.Cancel()
 	}
+}
 
-	// 2. Update current mode tracking
-	w.currentMode = mode
+// currentBuilder returns the builder of the active mode (see updateCurrentBuilder)
+func (w *TinyWasm) currentBuilder() *gobuild.GoBuild {
+	w.modeMu.RLock()
+	defer w.modeMu.RUnlock()
+	return w.activeBuilder
+}
 
-	// 3. Set activeBuilder based on mode
+// builderForMode returns the builder configured for the given mode shortcut
+func (w *TinyWasm) builderForMode(mode string) *gobuild.GoBuild {
 	switch mode {
 	case w.Config.BuildLargeSizeShortcut: // "L"
-		w.activeBuilder = w.builderLarge
+		return w.builderLarge
 	case w.Config.BuildMediumSizeShortcut: // "M"
-		w.activeBuilder = w.builderMedium
+		return w.builderMedium
 	case w.Config.BuildSmallSizeShortcut: // "S"
-		w.activeBuilder = w.builderSmall
+		return w.builderSmall
 	default:
-		w.activeBuilder = w.builderLarge // fallback to coding mode
+		return w.builderLarge // fallback to coding mode
 	}
 }
 
@@ -97,7 +362,7 @@ func (w *TinyWasm) updateCurrentBuilder(mode string) {
 func (w *TinyWasm) OutputRelativePath() string {
 	// FinalOutputPath() returns absolute path like: /tmp/test/deploy/edgeworker/app.wasm
 	// We need to extract the relative portion: deploy/edgeworker/app.wasm
-	fullPath := w.activeBuilder.FinalOutputPath()
+	fullPath := w.currentBuilder().FinalOutputPath()
 
 	// Remove AppRootDir prefix to get relative path
 	if strings.HasPrefix(fullPath, w.Config.AppRootDir) {
@@ -112,6 +377,6 @@ func (w *TinyWasm) OutputRelativePath() string {
 
 	// Fallback: construct from config values (which are already relative)
 	// Normalize to forward slashes for consistency
-	result := filepath.Join(w.Config.OutputDir, w.Config.OutputName+".wasm")
+	result := filepath.Join(w.Config.OutputDir, w.outName(w.Value())+".wasm")
 	return strings.ReplaceAll(result, "\\", "/")
 }
@@ -0,0 +1,236 @@
+package tinywasm
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestGcAndAsmFlags verifies GcFlags/AsmFlags reach the Go invocation and are ignored by TinyGo modes
+func TestGcAndAsmFlags(t *testing.T) {
+	var requested []string
+	w := New(&Config{
+		AppRootDir:              t.TempDir(),
+		DisableWasmExecJsOutput: true,
+		Logger:                  func(...any) {},
+		GcFlags: func(mode string) []string {
+			requested = append(requested, mode)
+			return []string{"all=-N", "-l"}
+		},
+		AsmFlags: func(mode string) []string {
+			return []string{"-trimpath=/src"}
+		},
+	})
+
+	args := w.builderLarge.BuildArguments()
+	for _, want := range []string{"-gcflags=all=-N -l", "-asmflags=-trimpath=/src"} {
+		if !slices.Contains(args, want) {
+			t.Errorf("Go build arguments %v missing %q", args, want)
+		}
+	}
+	if !slices.Contains(requested, w.Config.BuildLargeSizeShortcut) {
+		t.Errorf("GcFlags should receive the large mode, got %v", requested)
+	}
+
+	for _, mode := range []string{w.Config.BuildMediumSizeShortcut, w.Config.BuildSmallSizeShortcut} {
+		for _, arg := range w.compilingArguments(mode) {
+			if arg == "-gcflags=all=-N -l" || arg == "-asmflags=-trimpath=/src" {
+				t.Errorf("mode %s: TinyGo arguments should not include %q", mode, arg)
+			}
+		}
+	}
+}
+
+// TestLargeFastCompile verifies the fast-compile gcflags reach the Large build only
+func TestLargeFastCompile(t *testing.T) {
+	w := New(&Config{
+		AppRootDir:              t.TempDir(),
+		DisableWasmExecJsOutput: true,
+		Logger:                  func(...any) {},
+		LargeFastCompile:        true,
+	})
+
+	if args := w.builderLarge.BuildArguments(); !slices.Contains(args, "-gcflags=all=-N -l") {
+		t.Errorf("Large build arguments %v missing fast-compile gcflags", args)
+	}
+
+	// Merged into a single -gcflags with user flags
+	w.Config.GcFlags = func(string) []string { return []string{"-m"} }
+	if args := w.compilingArguments(w.Config.BuildLargeSizeShortcut); !slices.Contains(args, "-gcflags=all=-N -l -m") {
+		t.Errorf("Large build arguments %v should merge fast-compile and user gcflags", args)
+	}
+
+	w.Config.LargeFastCompile = false
+	if args := w.compilingArguments(w.Config.BuildLargeSizeShortcut); !slices.Contains(args, "-gcflags=-m") {
+		t.Errorf("arguments %v should only carry user gcflags when LargeFastCompile is off", args)
+	}
+}
+
+// TestCompilerArgs verifies the resolved arguments of each mode without building
+func TestCompilerArgs(t *testing.T) {
+	w := New(&Config{
+		AppRootDir:              "/project",
+		SourceDir:               "web",
+		OutputDir:               "web/public",
+		MainInputFile:           "main.go",
+		DisableWasmExecJsOutput: true,
+		Logger:                  func(...any) {},
+		CompilingArguments: func() []string {
+			return []string{"-X", "main.version=1.0"}
+		},
+	})
+
+	large := w.CompilerArgs("l")
+	expected := []string{"build", "-tags", "dev", "-ldflags=-X main.version=1.0", "-o", "/project/web/public/main_temp.wasm", "/project/web/main.go"}
+	if !slices.Equal(large, expected) {
+		t.Errorf("CompilerArgs(L) = %v, want %v", large, expected)
+	}
+
+	if small := w.CompilerArgs(w.Config.BuildSmallSizeShortcut); !slices.Contains(small, "-opt=z") {
+		t.Errorf("CompilerArgs(S) = %v, missing -opt=z", small)
+	}
+
+	if args := w.CompilerArgs("X"); args != nil {
+		t.Errorf("CompilerArgs(X) = %v, want nil for unknown mode", args)
+	}
+}
+
+// TestResolveCompiler verifies the compiler reported for the active mode
+func TestResolveCompiler(t *testing.T) {
+	w := New(&Config{AppRootDir: t.TempDir(), Logger: func(...any) {}})
+
+	command, args := w.ResolveCompiler()
+	if command != "go" || !slices.Equal(args, []string{"-tags", "dev"}) {
+		t.Errorf("Large: ResolveCompiler() = %s %v, want go [-tags dev]", command, args)
+	}
+
+	w.currentMode = w.Config.BuildSmallSizeShortcut
+	command, args = w.ResolveCompiler()
+	if command != "tinygo" || !slices.Contains(args, "-opt=z") {
+		t.Errorf("Small: ResolveCompiler() = %s %v, want tinygo with -opt=z", command, args)
+	}
+}
+
+func TestCodingBuildTags(t *testing.T) {
+	w := New(&Config{AppRootDir: t.TempDir(), Logger: func(...any) {}})
+	large := w.Config.BuildLargeSizeShortcut
+
+	if args := w.compilingArguments(large); !slices.Equal(args, []string{"-tags", "dev"}) {
+		t.Errorf("default Large arguments = %v, want [-tags dev]", args)
+	}
+
+	w.Config.CodingBuildTags = []string{"local", "debug"}
+	if args := w.compilingArguments(large); !slices.Equal(args, []string{"-tags", "local,debug"}) {
+		t.Errorf("custom Large arguments = %v, want [-tags local,debug]", args)
+	}
+
+	w.Config.CodingBuildTags = []string{}
+	if args := w.compilingArguments(large); len(args) != 0 {
+		t.Errorf("Large arguments with tags disabled = %v, want none", args)
+	}
+}
+
+// TestBuildTagsPerMode verifies the per-mode tags and the Large default
+func TestBuildTagsPerMode(t *testing.T) {
+	w := New(&Config{
+		AppRootDir:     t.TempDir(),
+		BuildTagsSmall: []string{"prod", "netgo"},
+		Logger:         func(...any) {},
+	})
+
+	if args := w.compilingArguments(w.Config.BuildLargeSizeShortcut); !slices.Equal(args, []string{"-tags", "dev"}) {
+		t.Errorf("Large arguments = %v, want the dev default", args)
+	}
+	if args := w.compilingArguments(w.Config.BuildMediumSizeShortcut); slices.Contains(args, "-tags") {
+		t.Errorf("Medium arguments = %v, want no tags", args)
+	}
+	small := w.compilingArguments(w.Config.BuildSmallSizeShortcut)
+	if i := slices.Index(small, "-tags"); i < 0 || small[i+1] != "prod,netgo" {
+		t.Errorf("Small arguments = %v, want -tags prod,netgo", small)
+	}
+
+	w.Config.BuildTagsLarge = []string{"wasm"}
+	if args := w.compilingArguments(w.Config.BuildLargeSizeShortcut); !slices.Equal(args, []string{"-tags", "wasm"}) {
+		t.Errorf("Large arguments = %v, want BuildTagsLarge to replace dev", args)
+	}
+}
+
+// TestCompileTimeouts verifies the per-mode timeouts and their defaults
+func TestCompileTimeouts(t *testing.T) {
+	w := New(&Config{
+		AppRootDir:              t.TempDir(),
+		TimeoutSmall:            5 * time.Minute,
+		DisableWasmExecJsOutput: true,
+		Logger:                  func(...any) {},
+	})
+
+	expected := map[string]time.Duration{
+		w.Config.BuildLargeSizeShortcut:  30 * time.Second,
+		w.Config.BuildMediumSizeShortcut: 60 * time.Second,
+		w.Config.BuildSmallSizeShortcut:  5 * time.Minute,
+	}
+	for mode, want := range expected {
+		if got := w.builderConfig(mode).Timeout; got != want {
+			t.Errorf("mode %s: Timeout = %v, want %v", mode, got, want)
+		}
+	}
+}
+
+// TestExtraEnv verifies ExtraEnv is added to every builder without duplicates
+func TestExtraEnv(t *testing.T) {
+	w := New(&Config{
+		AppRootDir: t.TempDir(),
+		GoFlags:    "-mod=vendor",
+		ExtraEnv:   []string{"CGO_ENABLED=0", "GOFLAGS=-mod=mod", "GOOS=linux"},
+		Logger:     func(...any) {},
+	})
+
+	large := w.builderConfig(w.Config.BuildLargeSizeShortcut).Env
+	want := []string{"GOOS=js", "GOARCH=wasm", "GOFLAGS=-mod=mod", "CGO_ENABLED=0"}
+	if !slices.Equal(large, want) {
+		t.Errorf("Large env = %v, want %v", large, want)
+	}
+
+	small := w.builderConfig(w.Config.BuildSmallSizeShortcut).Env
+	if !slices.Equal(small, []string{"CGO_ENABLED=0", "GOFLAGS=-mod=mod", "GOOS=linux"}) {
+		t.Errorf("Small env = %v, want ExtraEnv as is", small)
+	}
+}
+
+// TestLDFlags verifies LDFlags is evaluated per build and merged with -ldflags arguments
+func TestLDFlags(t *testing.T) {
+	version := "v1"
+	var logs []string
+	w := New(&Config{
+		AppRootDir: "/project",
+		SourceDir:  "web",
+		OutputDir:  "web/public",
+		LDFlags:    func() string { return "-s -w -X main.Version=" + version },
+		CompilingArguments: func() []string {
+			return []string{"-ldflags", "-X main.env=ci", "-trimpath", "-ldflags=-X main.region=eu", "-X", "main.commit=abc", "-X", "main.branch", "dev"}
+		},
+		Logger: func(msg ...any) { logs = append(logs, fmt.Sprint(msg...)) },
+	})
+	logs = nil
+
+	want := []string{"-ldflags=-s -w -X main.Version=v1 -X main.env=ci -X main.region=eu -X main.commit=abc -X main.branch=dev", "-tags", "dev", "-trimpath"}
+	if args := w.compilingArguments(w.Config.BuildLargeSizeShortcut); !slices.Equal(args, want) {
+		t.Errorf("Large arguments = %v, want %v", args, want)
+	}
+
+	version = "v2"
+	args := w.CompilerArgs(w.Config.BuildLargeSizeShortcut)
+	if !slices.Contains(args, "-ldflags=-s -w -X main.Version=v2 -X main.env=ci -X main.region=eu -X main.commit=abc -X main.branch=dev") || slices.ContainsFunc(args[2:], func(a string) bool { return strings.HasPrefix(a, "-ldflags") || a == "-X" }) {
+		t.Errorf("CompilerArgs = %v, want a single -ldflags with the new version", args)
+	}
+	if len(logs) != 0 {
+		t.Errorf("unexpected warnings for a Go mode: %v", logs)
+	}
+
+	w.compilingArguments(w.Config.BuildSmallSizeShortcut)
+	if len(logs) != 1 || !strings.Contains(logs[0], "TinyGo") {
+		t.Errorf("logs = %v, want a TinyGo ldflags warning", logs)
+	}
+}
@@ -0,0 +1,79 @@
+package tinywasm
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// bundlerConfigFiles maps bundler config file names to the bundler they belong to
+var bundlerConfigFiles = []struct{ bundler, pattern string }{
+	{"vite", "vite.config.*"},
+	{"webpack", "webpack.config.*"},
+	{"rollup", "rollup.config.*"},
+}
+
+// DetectedBundler returns the JavaScript bundler found in AppRootDir by New ("vite",
+// "webpack", "rollup", "esbuild", "parcel" or "npm" for an unrecognized package.json
+// wasm plugin), or "" when none. While one is detected generated pages are left to
+// the bundler: InitProject skips index.html unless Config.ForceHTMLGeneration is set.
+func (w *TinyWasm) DetectedBundler() string {
+	return w.bundler
+}
+
+// detectBundler looks for bundler config files or a package.json depending on a
+// wasm plugin in AppRootDir and warns that generated pages may conflict with it
+func (w *TinyWasm) detectBundler() {
+	w.bundler = bundlerIn(w.Config.AppRootDir)
+	if w.bundler == "" {
+		return
+	}
+
+	if w.Config.ForceHTMLGeneration {
+		w.Logger("Warning:", w.bundler, "bundler setup detected, HTML generation forced by ForceHTMLGeneration")
+	} else {
+		w.Logger("Warning:", w.bundler, "bundler setup detected, skipping HTML generation (set ForceHTMLGeneration to override)")
+	}
+}
+
+// bundlerIn returns the bundler configured in dir, "" when none
+func bundlerIn(dir string) string {
+	for _, c := range bundlerConfigFiles {
+		if matches, _ := filepath.Glob(filepath.Join(dir, c.pattern)); len(matches) > 0 {
+			return c.bundler
+		}
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, "package.json"))
+	if err != nil {
+		return ""
+	}
+	var pkg struct {
+		Dependencies    map[string]string `json:"dependencies"`
+		DevDependencies map[string]string `json:"devDependencies"`
+	}
+	if json.Unmarshal(data, &pkg) != nil {
+		return ""
+	}
+
+	for _, deps := range []map[string]string{pkg.Dependencies, pkg.DevDependencies} {
+		for name := range deps {
+			if !strings.Contains(name, "wasm") {
+				continue
+			}
+			for _, bundler := range []string{"vite", "webpack", "rollup", "esbuild", "parcel"} {
+				if strings.Contains(name, bundler) {
+					return bundler
+				}
+			}
+			return "npm"
+		}
+	}
+	return ""
+}
+
+// skipHTMLGeneration reports whether generated pages must be left to a detected bundler
+func (w *TinyWasm) skipHTMLGeneration() bool {
+	return w.bundler != "" && !w.Config.ForceHTMLGeneration
+}
@@ -0,0 +1,66 @@
+package tinywasm
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestDetectedBundler(t *testing.T) {
+	tests := []struct {
+		name, file, content, want string
+	}{
+		{"none", "", "", ""},
+		{"vite config", "vite.config.ts", "export default {}", "vite"},
+		{"webpack config", "webpack.config.js", "module.exports = {}", "webpack"},
+		{"wasm plugin", "package.json", `{"devDependencies": {"vite-plugin-wasm": "^3.0.0"}}`, "vite"},
+		{"unknown wasm plugin", "package.json", `{"dependencies": {"wasm-pack-helper": "1.0.0"}}`, "npm"},
+		{"package.json without wasm", "package.json", `{"dependencies": {"react": "18.0.0"}}`, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmp := t.TempDir()
+			if tt.file != "" {
+				if err := os.WriteFile(filepath.Join(tmp, tt.file), []byte(tt.content), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			w := New(&Config{AppRootDir: tmp, Logger: func(...any) {}})
+			if got := w.DetectedBundler(); got != tt.want {
+				t.Errorf("DetectedBundler() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestInitProjectSkipsHTMLWithBundler(t *testing.T) {
+	tmp := t.TempDir()
+	if err := os.WriteFile(filepath.Join(tmp, "vite.config.js"), []byte("export default {}"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	config := &Config{
+		AppRootDir:    tmp,
+		SourceDir:     "web",
+		OutputDir:     "web/public",
+		MainInputFile: "main.go",
+		Logger:        func(...any) {},
+	}
+	w := New(config)
+	if err := w.InitProject("example.com/app"); err != nil {
+		t.Fatalf("InitProject failed: %v", err)
+	}
+	indexPath := filepath.Join(tmp, "web", "public", "index.html")
+	if _, err := os.Stat(indexPath); !os.IsNotExist(err) {
+		t.Error("index.html should not be generated while a bundler is detected")
+	}
+
+	config.ForceHTMLGeneration = true
+	if err := w.InitProject(""); err != nil {
+		t.Fatalf("InitProject failed: %v", err)
+	}
+	if _, err := os.Stat(indexPath); err != nil {
+		t.Errorf("index.html should be generated with ForceHTMLGeneration: %v", err)
+	}
+}
@@ -0,0 +1,26 @@
+package tinywasm
+
+import "testing"
+
+func TestModeSwitchWillRecompile(t *testing.T) {
+	tmp := t.TempDir()
+	w := New(&Config{
+		AppRootDir:              tmp,
+		SourceDir:               "web",
+		OutputDir:               "web/public",
+		DisableWasmExecJsOutput: true,
+		Logger:                  func(...any) {},
+	})
+
+	if w.ModeSwitchWillRecompile("S") {
+		t.Error("no build runs while the main file does not exist")
+	}
+
+	writeWasmProject(t, tmp)
+
+	for mode, want := range map[string]bool{"L": false, "m": true, "S": true, "X": false} {
+		if got := w.ModeSwitchWillRecompile(mode); got != want {
+			t.Errorf("ModeSwitchWillRecompile(%q) = %v, want %v", mode, got, want)
+		}
+	}
+}
@@ -0,0 +1,467 @@
+package tinywasm
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/cdvelop/gobuild"
+	. "github.com/cdvelop/tinystring"
+)
+
+// compileWith compiles using the given builder and applies the post-build
+// steps to its output. When b compiles asynchronously (Config.Callback) the
+// post-build steps run from the builder callback instead, which releases
+// the output lock taken here (see asyncCallback). A build of b still
+// running is cancelled and restarted (see acquireBuilder).
+func (w *TinyWasm) compileWith(b *gobuild.GoBuild, mode string) error {
+	if w.noToolchain {
+		if w.skipBuildWithoutToolchain() {
+			return nil
+		}
+		err := Err(noToolchainMessage)
+		w.notifyBuildResult(b, mode, err)
+		return err
+	}
+
+	lock := w.acquireBuilder(b)
+	async := w.asyncBuildOf(b)
+	if async != nil {
+		async.lock = lock // released by the builder callback
+	}
+	w.buildStarted(mode)
+	err := b.CompileProgram()
+	if async != nil {
+		return err
+	}
+	err = w.afterCompile(b, mode, err)
+	w.buildFinished(mode, err)
+	lock.Unlock() // before the hooks, which may start another build of b
+	w.notifyBuildResult(b, mode, err)
+	return err
+}
+
+// BuildMode compiles mode synchronously without changing the active mode, eg: to emit
+// a Small artifact from CI while the dev state stays in Large. The active mode gets the
+// post-build steps; another mode only gets its wasm written (see buildArtifact). When
+// mode shares the active mode's output file it is written to its own file instead,
+// eg: main.S.wasm, so the active mode's wasm is kept.
+func (w *TinyWasm) BuildMode(mode string) error {
+	mode = Convert(mode).ToUpper().String()
+	if err := w.validateMode(mode); err != nil {
+		return err
+	}
+
+	if w.requiresTinyGo(mode) {
+		w.verifyTinyGoInstallationStatus()
+		if !w.tinyGoInstalled {
+			return w.handleTinyGoMissing()
+		}
+		if err := w.checkMinTinyGoVersion(); err != nil {
+			return err
+		}
+	}
+
+	if mode == w.Value() {
+		_, err := w.buildSync(mode, w.outName(mode))
+		return err
+	}
+
+	outName := w.outName(mode)
+	if outName == w.outName(w.Value()) {
+		outName = w.Config.OutputName + "." + mode
+	}
+	return w.buildArtifact(mode, outName)
+}
+
+// CompileAllModes builds every mode to its own file next to the regular output,
+// eg: main.L.wasm, main.M.wasm and main.S.wasm, and returns the size of each by mode
+// shortcut. TinyGo modes are skipped with a 0 size when TinyGo is not installed. The
+// active mode, the regular output and the post-build state are left untouched.
+func (w *TinyWasm) CompileAllModes() (map[string]int64, error) {
+	return w.modeArtifactSizes(false)
+}
+
+// CompareSizes returns the size of each mode's wasm by mode shortcut, like
+// CompileAllModes, but reuses the per-mode artifacts (eg: main.S.wasm) that are newer
+// than every source file of the project: only missing or stale ones are compiled.
+func (w *TinyWasm) CompareSizes() (map[string]int64, error) {
+	return w.modeArtifactSizes(true)
+}
+
+// modeArtifactSizes builds the per-mode artifacts of CompileAllModes and returns their
+// sizes. With reuseFresh, artifacts newer than the project sources are not rebuilt.
+func (w *TinyWasm) modeArtifactSizes(reuseFresh bool) (map[string]int64, error) {
+	if w.noToolchain {
+		return nil, Err(noToolchainMessage)
+	}
+
+	var sourcesModTime time.Time
+	if reuseFresh {
+		sourcesModTime = w.latestSourceModTime()
+	}
+
+	sizes := make(map[string]int64, 3)
+	for _, mode := range []string{w.Config.BuildLargeSizeShortcut, w.Config.BuildMediumSizeShortcut, w.Config.BuildSmallSizeShortcut} {
+		config := w.builderConfig(mode)
+		config.OutName = w.Config.OutputName + "." + mode
+		b := gobuild.New(config)
+		outputPath := b.FinalOutputPath()
+
+		if reuseFresh {
+			if info, err := os.Stat(outputPath); err == nil && info.ModTime().After(sourcesModTime) {
+				sizes[mode] = info.Size()
+				continue
+			}
+		}
+
+		if w.requiresTinyGo(mode) {
+			w.verifyTinyGoInstallationStatus()
+			if !w.tinyGoInstalled {
+				w.Logger("TinyGo not installed, skipping mode", mode)
+				sizes[mode] = 0
+				continue
+			}
+		}
+
+		if err := b.CompileProgram(); err != nil {
+			return sizes, Err("compiling mode", mode+":", err.Error())
+		}
+
+		if w.Config.OutputFilePerm != 0 {
+			if err := os.Chmod(outputPath, w.Config.OutputFilePerm); err != nil {
+				return sizes, Err("setting wasm output permissions:", err)
+			}
+		}
+
+		info, err := os.Stat(outputPath)
+		if err != nil {
+			return sizes, Err("reading wasm output size:", err)
+		}
+		sizes[mode] = info.Size()
+	}
+	return sizes, nil
+}
+
+// latestSourceModTime returns the newest modification time of the .go, go.mod and
+// go.sum files of AppRootDir, skipping the directories project detection skips
+func (w *TinyWasm) latestSourceModTime() time.Time {
+	var latest time.Time
+	filepath.WalkDir(w.Config.AppRootDir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if d.IsDir() {
+			if w.skipDetectionDir(p, d.Name()) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if name := d.Name(); filepath.Ext(name) != ".go" && name != "go.mod" && name != "go.sum" {
+			return nil
+		}
+		if info, err := d.Info(); err == nil && info.ModTime().After(latest) {
+			latest = info.ModTime()
+		}
+		return nil
+	})
+	return latest
+}
+
+// buildArtifact compiles mode to outName (without extension) and only writes the wasm,
+// like CompileAllModes: the state of the active mode (LastOutputSize, output manifest,
+// hashed name, build history) is left untouched.
+func (w *TinyWasm) buildArtifact(mode, outName string) error {
+	if w.noToolchain {
+		return Err(noToolchainMessage)
+	}
+
+	config := w.builderConfig(mode)
+	config.OutName = outName
+	b := gobuild.New(config)
+
+	lock := w.outputLock(b.FinalOutputPath())
+	lock.Lock()
+	defer lock.Unlock()
+
+	if err := b.CompileProgram(); err != nil {
+		return w.compilerDiagnostics(err)
+	}
+	if w.Config.OutputFilePerm != 0 {
+		if err := os.Chmod(b.FinalOutputPath(), w.Config.OutputFilePerm); err != nil {
+			return Err("setting wasm output permissions:", err)
+		}
+	}
+	return nil
+}
+
+// buildSync compiles mode to outName (without extension) with a dedicated synchronous
+// builder (even when Config.Callback is set) and applies the post-build steps. Returns
+// the path of the wasm output.
+func (w *TinyWasm) buildSync(mode, outName string) (string, error) {
+	if w.noToolchain {
+		return "", Err(noToolchainMessage) // callers need a real output
+	}
+
+	config := w.builderConfig(mode)
+	config.OutName = outName
+	b := gobuild.New(config)
+
+	// Waits for a file-event build of the same output instead of cancelling it
+	lock := w.outputLock(b.FinalOutputPath())
+	lock.Lock()
+	defer lock.Unlock()
+
+	w.buildStarted(mode)
+	err := w.afterCompile(b, mode, b.CompileProgram())
+	w.buildFinished(mode, err)
+
+	if err != nil {
+		return "", err
+	}
+	return w.outputPathFor(b), nil
+}
+
+// IsBuilding reports whether a build is currently in progress on any builder,
+// including its post-build steps. Async builds count until just before
+// Config.Callback receives the result.
+func (w *TinyWasm) IsBuilding() bool {
+	w.buildMu.Lock()
+	defer w.buildMu.Unlock()
+	return w.activeBuilds > 0
+}
+
+// LastOutputSize returns the size in bytes of the wasm produced by the last successful
+// build of the active mode in this session, recorded when the build finished so it never
+// observes a file mid-rewrite. Before any build of the mode it falls back to the current
+// output file (0 if missing).
+func (w *TinyWasm) LastOutputSize() int64 {
+	mode := w.Value()
+	w.buildMu.Lock()
+	size, built := w.outputSizes[mode]
+	w.buildMu.Unlock()
+
+	if built {
+		return size
+	}
+	if info, err := os.Stat(w.outputPathFor(w.currentBuilder())); err == nil {
+		return info.Size()
+	}
+	return 0
+}
+
+// GetWasmSize returns the size in bytes of the wasm output of the active mode as it
+// is on disk now (main.wasm, or main.debug.wasm for Medium with DebugOutputSuffix).
+// Unlike LastOutputSize it fails when the output has not been built yet.
+func (w *TinyWasm) GetWasmSize() (int64, error) {
+	outputPath := w.outputPathFor(w.currentBuilder())
+	info, err := os.Stat(outputPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return 0, Errf("wasm output %s not built yet for mode %s", outputPath, w.Value())
+		}
+		return 0, Err("reading wasm output size:", err)
+	}
+	return info.Size(), nil
+}
+
+// LastBuildDuration returns how long the most recent build took, failed or not,
+// including its post-build steps, eg: to show "compiled in 1.3s". It is 0 before any
+// build and while a build is running.
+func (w *TinyWasm) LastBuildDuration() time.Duration {
+	w.buildMu.Lock()
+	defer w.buildMu.Unlock()
+	return w.lastBuildDuration
+}
+
+// recordOutputSize stores the output size of a finished build of mode for LastOutputSize
+func (w *TinyWasm) recordOutputSize(mode string, size int64) {
+	w.buildMu.Lock()
+	if w.outputSizes == nil {
+		w.outputSizes = make(map[string]int64)
+	}
+	w.outputSizes[mode] = size
+	w.buildMu.Unlock()
+}
+
+// buildStarted records a build of mode that was just started and publishes its "start" event
+func (w *TinyWasm) buildStarted(mode string) {
+	w.buildMu.Lock()
+	w.activeBuilds++
+	w.lastBuildDuration = 0
+	if w.buildStartTimes == nil {
+		w.buildStartTimes = make(map[string]time.Time)
+	}
+	w.buildStartTimes[mode] = time.Now()
+	w.buildMu.Unlock()
+
+	w.publishEvent(BuildEvent{Type: "start", Mode: mode})
+}
+
+// buildFinished records the end of a build started with buildStarted, adds its
+// final result to the build history and publishes its "end" event
+func (w *TinyWasm) buildFinished(mode string, err error) {
+	w.buildMu.Lock()
+	if w.activeBuilds > 0 {
+		w.activeBuilds--
+	}
+
+	result := BuildResult{Mode: mode, Err: err, FinishedAt: time.Now()}
+	if start, ok := w.buildStartTimes[mode]; ok {
+		result.Duration = result.FinishedAt.Sub(start)
+	}
+	w.lastBuildDuration = result.Duration
+	if err == nil {
+		result.Size = w.outputSizes[mode]
+	}
+	w.history.add(result, w.Config.HistorySize)
+	w.buildMu.Unlock()
+
+	w.publishEvent(endEvent(result))
+}
+
+// asyncCallback returns the gobuild callback of the builder of build: it runs the
+// post-build steps of the build compileWith started on that builder, releases the
+// output lock compileWith took and then calls the build hooks and Config.Callback.
+func (w *TinyWasm) asyncCallback(mode string, build *asyncBuild) gobuild.CompileCallback {
+	return func(err error) {
+		result := w.afterCompile(build.builder, mode, err)
+		w.buildFinished(mode, result)
+		build.lock.Unlock() // before the hooks and Callback, which may start another build
+		w.notifyBuildResult(build.builder, mode, result)
+		w.Callback(result)
+	}
+}
+
+// notifyBuildResult finishes a build of mode on b: a failed one is reported to
+// Config.OnBuildError. A successful one (re)writes wasm_exec.js for the active mode,
+// whether or not a hook is set, writes build-stats.json for the main program
+// (Config.EmitBuildStats) and then calls Config.OnBuildSuccess, unless wasm_exec.js
+// is not available.
+func (w *TinyWasm) notifyBuildResult(b *gobuild.GoBuild, mode string, err error) {
+	if err != nil {
+		if w.Config.OnBuildError != nil {
+			w.Config.OnBuildError(mode, err)
+		}
+		return
+	}
+
+	writesWasmExecJs := w.wasmProject && !w.Config.DisableWasmExecJsOutput && !w.skipsWasmExecJs(mode) && mode == w.Value()
+	if writesWasmExecJs {
+		w.wasmProjectWriteOrReplaceWasmExecJsOutput()
+	}
+	if w.Config.EmitBuildStats && !w.isEntryBuilder(b) {
+		w.writeBuildStats(b, mode)
+	}
+	if w.Config.OnBuildSuccess == nil {
+		return
+	}
+
+	if writesWasmExecJs {
+		if _, err := os.Stat(w.WasmExecJsOutputPath()); err != nil {
+			w.Logger("Build succeeded but wasm_exec.js is not available:", err)
+			return
+		}
+	}
+
+	outputPath := w.outputPathFor(b)
+	info, err := os.Stat(outputPath)
+	if err != nil {
+		w.Logger("Build succeeded but its output is not available:", err)
+		return
+	}
+	w.Config.OnBuildSuccess(outputPath, info.Size())
+}
+
+// afterCompile runs once a build on b has finished and returns the final build result.
+// A failed build or post-build step removes the compressed copies, which would
+// otherwise no longer be guaranteed to match the wasm. Compiler failures carry the
+// compiler output (see compilerDiagnostics).
+func (w *TinyWasm) afterCompile(b *gobuild.GoBuild, mode string, err error) error {
+	if err == nil {
+		err = w.postProcess(b, mode)
+	} else {
+		err = w.compilerDiagnostics(err)
+	}
+	w.recordDiagnostics(err)
+	if err != nil {
+		removeCompressedCopies(b.FinalOutputPath())
+	}
+	if !w.isEntryBuilder(b) {
+		w.recordFingerprint(b, mode, err)
+	}
+	return err
+}
+
+// postProcess runs the post-build steps on the output of a successful build. Extra
+// entry points (see Config.ExtraEntryPoints) only get the file steps: size, exports,
+// output name and hashing apply to the main program.
+func (w *TinyWasm) postProcess(b *gobuild.GoBuild, mode string) error {
+	w.publishEvent(BuildEvent{Type: "progress", Mode: mode, Message: "compiled, running post-build steps"})
+
+	outputPath := b.FinalOutputPath()
+	main := !w.isEntryBuilder(b)
+
+	if w.Config.OutputFilePerm != 0 {
+		if err := os.Chmod(outputPath, w.Config.OutputFilePerm); err != nil {
+			return Err("setting wasm output permissions:", err)
+		}
+	}
+
+	if main {
+		if info, err := os.Stat(outputPath); err == nil {
+			w.recordOutputSize(mode, info.Size())
+			if err := w.checkMaxWasmBytes(mode, info.Size()); err != nil {
+				return err
+			}
+		}
+
+		if err := w.verifyRequiredExports(outputPath); err != nil {
+			return err
+		}
+
+		w.recordOutputName(mode)
+	}
+
+	if w.Config.HashedOutputName && main {
+		hashed, err := applyHashedOutputName(outputPath)
+		if err != nil {
+			return err
+		}
+		outputPath = hashed
+	}
+
+	if w.Config.EmitGzip {
+		if err := writeGzipCopy(outputPath, w.gzipLevel(), w.outputFilePerm()); err != nil {
+			return Err("writing gzip copy:", err)
+		}
+	}
+
+	if w.Config.EmitBrotli {
+		if err := writeBrotliCopy(outputPath, w.brotliQuality(), w.outputFilePerm()); err != nil {
+			return Err("writing brotli copy:", err)
+		}
+	}
+
+	if w.Config.EmitSymbols && w.requiresTinyGo(mode) {
+		if err := writeSymbolsFile(outputPath, w.outputFilePerm()); err != nil {
+			w.Logger("Warning: could not write symbols file:", err)
+		}
+	}
+
+	// The generated JS fetches the wasm by its hashed name
+	if w.Config.HashedOutputName && main && mode == w.Value() && !w.Config.DisableWasmExecJsOutput {
+		w.wasmProjectWriteOrReplaceWasmExecJsOutput()
+	}
+
+	return nil
+}
+
+// outputFilePerm returns the permissions for files written by the post-build steps
+func (w *TinyWasm) outputFilePerm() os.FileMode {
+	if w.Config.OutputFilePerm != 0 {
+		return w.Config.OutputFilePerm
+	}
+	return 0644
+}
@@ -0,0 +1,619 @@
+package tinywasm
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+// writeWasmProject creates a minimal buildable project (go.mod + web/main.go) in dir,
+// with an empty web/public output directory
+func writeWasmProject(t *testing.T, dir string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Join(dir, "web", "public"), 0755); err != nil {
+		t.Fatalf("creating output dir: %v", err)
+	}
+	files := map[string]string{
+		"go.mod":      "module test\n\ngo 1.21\n",
+		"web/main.go": "package main\n\nfunc main() {\n\tprintln(\"hello wasm\")\n}\n",
+	}
+	for name, content := range files {
+		p := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+			t.Fatalf("creating dir: %v", err)
+		}
+		if err := os.WriteFile(p, []byte(content), 0644); err != nil {
+			t.Fatalf("writing %s: %v", name, err)
+		}
+	}
+}
+
+// TestIsBuildingTracksAsyncBuild verifies IsBuilding covers an async build until its callback
+func TestIsBuildingTracksAsyncBuild(t *testing.T) {
+	tmp := t.TempDir()
+	writeWasmProject(t, tmp)
+
+	done := make(chan bool, 1)
+	var w *TinyWasm
+	w = New(&Config{
+		AppRootDir:              tmp,
+		SourceDir:               "web",
+		OutputDir:               "web/public",
+		MainInputFile:           "main.go",
+		DisableWasmExecJsOutput: true,
+		Logger:                  func(...any) {},
+		Callback: func(err error) {
+			if err != nil {
+				t.Errorf("build failed: %v", err)
+			}
+			done <- w.IsBuilding()
+		},
+	})
+
+	if w.IsBuilding() {
+		t.Fatal("IsBuilding should be false before any build")
+	}
+
+	if err := w.RecompileMainWasm(); err != nil {
+		t.Fatalf("RecompileMainWasm failed: %v", err)
+	}
+	if !w.IsBuilding() {
+		t.Error("IsBuilding should be true while the async build runs")
+	}
+
+	select {
+	case buildingInCallback := <-done:
+		if buildingInCallback {
+			t.Error("IsBuilding should be false once the callback receives the result")
+		}
+	case <-time.After(2 * time.Minute):
+		t.Fatal("timed out waiting for build callback")
+	}
+
+	if w.IsBuilding() {
+		t.Error("IsBuilding should be false after the build finished")
+	}
+}
+
+// TestOutputFilePerm verifies post-build steps apply Config.OutputFilePerm to the files they touch
+func TestOutputFilePerm(t *testing.T) {
+	tmp := t.TempDir()
+	w := New(&Config{
+		AppRootDir:              tmp,
+		OutputDir:               "public",
+		DisableWasmExecJsOutput: true,
+		Logger:                  func(...any) {},
+		EmitSymbols:             true,
+		OutputFilePerm:          0600,
+	})
+
+	output := filepath.Join(tmp, "public", "main.wasm")
+	if err := os.MkdirAll(filepath.Dir(output), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(output, buildTestWasmModuleWithNames("main.main"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := w.afterCompile(w.builderMedium, w.Config.BuildMediumSizeShortcut, nil); err != nil {
+		t.Fatalf("afterCompile failed: %v", err)
+	}
+
+	for _, p := range []string{output, output + symbolsFileExtension} {
+		info, err := os.Stat(p)
+		if err != nil {
+			t.Fatalf("stat %s: %v", p, err)
+		}
+		if perm := info.Mode().Perm(); perm != 0600 {
+			t.Errorf("%s permissions = %o, want 600", filepath.Base(p), perm)
+		}
+	}
+}
+
+// TestLastOutputSize verifies the size recorded by the last build wins over the file on disk
+func TestLastOutputSize(t *testing.T) {
+	tmp := t.TempDir()
+	w := New(&Config{
+		AppRootDir:              tmp,
+		OutputDir:               "public",
+		DisableWasmExecJsOutput: true,
+		Logger:                  func(...any) {},
+	})
+
+	if size := w.LastOutputSize(); size != 0 {
+		t.Errorf("LastOutputSize() = %d before any output, want 0", size)
+	}
+
+	output := filepath.Join(tmp, "public", "main.wasm")
+	if err := os.MkdirAll(filepath.Dir(output), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(output, make([]byte, 10), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if size := w.LastOutputSize(); size != 10 {
+		t.Errorf("LastOutputSize() = %d, want 10 from the existing file", size)
+	}
+
+	if err := w.afterCompile(w.builderLarge, w.Config.BuildLargeSizeShortcut, nil); err != nil {
+		t.Fatalf("afterCompile failed: %v", err)
+	}
+
+	// A rewrite in progress does not affect the recorded size
+	if err := os.WriteFile(output, make([]byte, 3), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if size := w.LastOutputSize(); size != 10 {
+		t.Errorf("LastOutputSize() = %d, want 10 recorded by the build", size)
+	}
+
+	// Another mode built to its own file (eg: BuildMode) doesn't change the active size
+	w.recordOutputSize(w.Config.BuildSmallSizeShortcut, 7)
+	if size := w.LastOutputSize(); size != 10 {
+		t.Errorf("LastOutputSize() = %d, want 10 from the active mode only", size)
+	}
+	w.updateCurrentBuilder(w.Config.BuildSmallSizeShortcut)
+	if size := w.LastOutputSize(); size != 7 {
+		t.Errorf("LastOutputSize() = %d, want 7 recorded for Small", size)
+	}
+}
+
+// TestGetWasmSize verifies GetWasmSize follows the active mode output
+func TestGetWasmSize(t *testing.T) {
+	tmp := t.TempDir()
+	w := New(&Config{
+		AppRootDir:              tmp,
+		OutputDir:               "public",
+		DebugOutputSuffix:       ".debug",
+		DisableWasmExecJsOutput: true,
+		Logger:                  func(...any) {},
+	})
+
+	if _, err := w.GetWasmSize(); err == nil {
+		t.Error("expected an error before the output exists")
+	}
+
+	if err := os.MkdirAll(filepath.Join(tmp, "public"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	for name, size := range map[string]int{"main.wasm": 300, "main.debug.wasm": 200} {
+		if err := os.WriteFile(filepath.Join(tmp, "public", name), make([]byte, size), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if size, err := w.GetWasmSize(); err != nil || size != 300 {
+		t.Errorf("GetWasmSize() = %d, %v; want 300 for Large", size, err)
+	}
+	w.updateCurrentBuilder(w.Config.BuildMediumSizeShortcut)
+	if size, err := w.GetWasmSize(); err != nil || size != 200 {
+		t.Errorf("GetWasmSize() = %d, %v; want 200 for Medium", size, err)
+	}
+}
+
+// TestBuildMode verifies BuildMode compiles the requested mode without switching the active one
+func TestBuildMode(t *testing.T) {
+	tmp := t.TempDir()
+	writeWasmProject(t, tmp)
+
+	w := New(&Config{
+		AppRootDir:              tmp,
+		SourceDir:               "web",
+		OutputDir:               "web/public",
+		MainInputFile:           "main.go",
+		DisableWasmExecJsOutput: true,
+		Logger:                  func(...any) {},
+	})
+
+	w.updateCurrentBuilder(w.Config.BuildSmallSizeShortcut)
+	active := w.activeBuilder
+
+	if err := w.BuildMode("x"); err == nil {
+		t.Error("expected error for invalid mode")
+	}
+
+	// Small and Large share main.wasm: the active Small output must be kept
+	activeOutput := filepath.Join(tmp, "web", "public", "main.wasm")
+	if err := os.WriteFile(activeOutput, []byte("small"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// A non-active mode only writes its wasm: no hashed rename nor active mode state
+	w.Config.HashedOutputName = true
+	if err := w.BuildMode("l"); err != nil {
+		t.Fatalf("BuildMode failed: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(tmp, "web", "public", "main.L.wasm")); err != nil {
+		t.Errorf("output not written: %v", err)
+	}
+	if data, _ := os.ReadFile(activeOutput); string(data) != "small" {
+		t.Error("BuildMode overwrote the active mode's output")
+	}
+	if size := w.LastOutputSize(); size != int64(len("small")) {
+		t.Errorf("LastOutputSize() = %d, want the active mode's output size", size)
+	}
+	if _, err := os.Stat(w.outputManifestPath()); err == nil {
+		t.Error("BuildMode of a non-active mode should not update the output manifest")
+	}
+	if history := w.BuildHistory(0); len(history) != 0 {
+		t.Errorf("BuildMode of a non-active mode should not be recorded, got %v", history)
+	}
+
+	if w.Value() != w.Config.BuildSmallSizeShortcut || w.activeBuilder != active {
+		t.Errorf("BuildMode changed the active mode to %s", w.Value())
+	}
+}
+
+// TestCompileAllModesSizes verifies CompileAllModes writes one output per mode and reports its size
+func TestCompileAllModesSizes(t *testing.T) {
+	tmp := t.TempDir()
+	writeWasmProject(t, tmp)
+
+	w := New(&Config{
+		AppRootDir:              tmp,
+		SourceDir:               "web",
+		OutputDir:               "web/public",
+		MainInputFile:           "main.go",
+		DisableWasmExecJsOutput: true,
+		Logger:                  func(...any) {},
+	})
+
+	sizes, err := w.CompileAllModes()
+	if err != nil {
+		t.Fatalf("CompileAllModes failed: %v", err)
+	}
+	if len(sizes) != 3 {
+		t.Errorf("CompileAllModes() = %v, want one entry per mode", sizes)
+	}
+
+	for mode, size := range sizes {
+		info, err := os.Stat(filepath.Join(tmp, "web", "public", "main."+mode+".wasm"))
+		if size == 0 && w.requiresTinyGo(mode) && !w.tinyGoInstalled {
+			continue // skipped without TinyGo
+		}
+		if err != nil || info.Size() != size {
+			t.Errorf("mode %s: reported size %d, output on disk: %v", mode, size, err)
+		}
+	}
+	if sizes[w.Config.BuildLargeSizeShortcut] == 0 {
+		t.Error("Large mode must always be compiled")
+	}
+
+	if _, err := os.Stat(filepath.Join(tmp, "web", "public", "main.wasm")); !os.IsNotExist(err) {
+		t.Error("CompileAllModes should not write the regular output")
+	}
+	if w.Value() != w.Config.BuildLargeSizeShortcut {
+		t.Errorf("active mode changed to %s", w.Value())
+	}
+}
+
+// TestLastBuildDuration verifies the duration is reset when a build starts and set when it ends
+func TestLastBuildDuration(t *testing.T) {
+	w := New(&Config{
+		AppRootDir:              t.TempDir(),
+		DisableWasmExecJsOutput: true,
+		Logger:                  func(...any) {},
+	})
+
+	if d := w.LastBuildDuration(); d != 0 {
+		t.Errorf("LastBuildDuration() = %v before any build, want 0", d)
+	}
+
+	mode := w.Config.BuildLargeSizeShortcut
+	w.buildStarted(mode)
+	time.Sleep(10 * time.Millisecond)
+	w.buildFinished(mode, errors.New("build failed"))
+
+	first := w.LastBuildDuration()
+	if first < 10*time.Millisecond {
+		t.Errorf("LastBuildDuration() = %v, want at least 10ms for a failed build", first)
+	}
+
+	w.buildStarted(mode)
+	if d := w.LastBuildDuration(); d != 0 {
+		t.Errorf("LastBuildDuration() = %v while building, want 0", d)
+	}
+	w.buildFinished(mode, nil)
+	if d := w.LastBuildDuration(); d >= first {
+		t.Errorf("LastBuildDuration() = %v, want the latest build only (< %v)", d, first)
+	}
+}
+
+// TestOnBuildSuccess verifies the hook runs after a successful build with wasm_exec.js in place, never on failures
+func TestOnBuildSuccess(t *testing.T) {
+	tmp := t.TempDir()
+	writeWasmProject(t, tmp)
+
+	var calls int
+	var gotPath string
+	var gotSize int64
+	w := New(&Config{
+		AppRootDir:          tmp,
+		SourceDir:           "web",
+		OutputDir:           "web/public",
+		WasmExecJsOutputDir: "web/js",
+		MainInputFile:       "main.go",
+		Logger:              func(...any) {},
+		OnBuildSuccess: func(outputPath string, size int64) {
+			calls++
+			gotPath, gotSize = outputPath, size
+		},
+	})
+
+	wasmExecJs := filepath.Join(tmp, "web", "js", "wasm_exec.js")
+	os.Remove(wasmExecJs) // eg: removed by a clean step
+
+	if err := w.RecompileMainWasm(); err != nil {
+		t.Fatalf("RecompileMainWasm failed: %v", err)
+	}
+	if calls != 1 {
+		t.Fatalf("OnBuildSuccess called %d times, want 1", calls)
+	}
+
+	output := filepath.Join(tmp, "web", "public", "main.wasm")
+	info, err := os.Stat(output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if gotPath != output || gotSize != info.Size() {
+		t.Errorf("OnBuildSuccess(%s, %d), want (%s, %d)", gotPath, gotSize, output, info.Size())
+	}
+	if _, err := os.Stat(wasmExecJs); err != nil {
+		t.Errorf("wasm_exec.js should exist when OnBuildSuccess runs: %v", err)
+	}
+
+	if err := os.WriteFile(filepath.Join(tmp, "web", "main.go"), []byte("package main\n\nfunc main() { undefined() }\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.RecompileMainWasm(); err == nil {
+		t.Fatal("expected build error")
+	}
+	if calls != 1 {
+		t.Errorf("OnBuildSuccess must not be called for a failed build")
+	}
+}
+
+// TestBuildWritesWasmExecJsWithoutHooks verifies a successful build restores wasm_exec.js
+// whether or not OnBuildSuccess is set: the hook only notifies
+func TestBuildWritesWasmExecJsWithoutHooks(t *testing.T) {
+	tmp := t.TempDir()
+	writeWasmProject(t, tmp)
+
+	w := New(&Config{
+		AppRootDir:          tmp,
+		SourceDir:           "web",
+		OutputDir:           "web/public",
+		WasmExecJsOutputDir: "web/js",
+		MainInputFile:       "main.go",
+		Logger:              func(...any) {},
+	})
+
+	wasmExecJs := filepath.Join(tmp, "web", "js", "wasm_exec.js")
+	os.Remove(wasmExecJs) // eg: removed by a clean step
+
+	if err := w.RecompileMainWasm(); err != nil {
+		t.Fatalf("RecompileMainWasm failed: %v", err)
+	}
+	if _, err := os.Stat(wasmExecJs); err != nil {
+		t.Errorf("wasm_exec.js should be written after a build without hooks: %v", err)
+	}
+}
+
+// TestOnBuildSuccessRebuilds verifies a hook starting another build of the same output
+// does not deadlock: the output lock is released before the hooks run
+func TestOnBuildSuccessRebuilds(t *testing.T) {
+	tmp := t.TempDir()
+	writeWasmProject(t, tmp)
+
+	var w *TinyWasm
+	var calls int
+	var hookErr error
+	w = New(&Config{
+		AppRootDir:              tmp,
+		SourceDir:               "web",
+		OutputDir:               "web/public",
+		MainInputFile:           "main.go",
+		DisableWasmExecJsOutput: true,
+		Logger:                  func(...any) {},
+		OnBuildSuccess: func(string, int64) {
+			calls++
+			if calls == 1 {
+				hookErr = w.RecompileMainWasm()
+			}
+		},
+	})
+
+	done := make(chan error, 1)
+	go func() { done <- w.RecompileMainWasm() }()
+
+	select {
+	case err := <-done:
+		if err != nil || hookErr != nil {
+			t.Fatalf("RecompileMainWasm = %v, from the hook = %v", err, hookErr)
+		}
+	case <-time.After(2 * time.Minute):
+		t.Fatal("RecompileMainWasm from OnBuildSuccess deadlocked")
+	}
+	if calls != 2 {
+		t.Errorf("OnBuildSuccess called %d times, want 2", calls)
+	}
+}
+
+// TestOnBuildError verifies failed builds report the active mode while still returning the error
+func TestOnBuildError(t *testing.T) {
+	tmp := t.TempDir()
+	writeWasmProject(t, tmp)
+	if err := os.WriteFile(filepath.Join(tmp, "web", "main.go"), []byte("package main\n\nfunc main() { undefined() }\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var modes []string
+	w := New(&Config{
+		AppRootDir:              tmp,
+		SourceDir:               "web",
+		OutputDir:               "web/public",
+		MainInputFile:           "main.go",
+		DisableWasmExecJsOutput: true,
+		Logger:                  func(...any) {},
+		OnBuildError: func(mode string, err error) {
+			if err == nil {
+				t.Error("OnBuildError called with a nil error")
+			}
+			modes = append(modes, mode)
+		},
+	})
+
+	if err := w.RecompileMainWasm(); err == nil {
+		t.Fatal("expected RecompileMainWasm to return the build error")
+	}
+	if err := w.NewFileEvent("main.go", ".go", filepath.Join(tmp, "web", "main.go"), "write"); err == nil {
+		t.Fatal("expected NewFileEvent to return the build error")
+	}
+
+	want := []string{w.Config.BuildLargeSizeShortcut, w.Config.BuildLargeSizeShortcut}
+	if len(modes) != len(want) || modes[0] != want[0] || modes[1] != want[1] {
+		t.Errorf("OnBuildError modes = %v, want %v", modes, want)
+	}
+}
+
+// TestCompileWithNeverOverlaps verifies concurrent builds of the same builder run one
+// after the other, the newest request cancelling the running one
+func TestCompileWithNeverOverlaps(t *testing.T) {
+	tmp := t.TempDir()
+	writeWasmProject(t, tmp)
+
+	w := New(&Config{
+		AppRootDir:              tmp,
+		SourceDir:               "web",
+		OutputDir:               "web/public",
+		DisableWasmExecJsOutput: true,
+		Logger:                  func(...any) {},
+	})
+	events, cancel := w.Events()
+	defer cancel()
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 3)
+	for range 3 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs <- w.compileWith(w.activeBuilder, w.Value())
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	succeeded := 0
+	for err := range errs {
+		if err == nil {
+			succeeded++
+		}
+	}
+	if succeeded == 0 {
+		t.Error("the last build should succeed")
+	}
+
+	running := 0
+	for len(events) > 0 {
+		switch e := <-events; e.Type {
+		case "start":
+			if running++; running > 1 {
+				t.Fatal("a build started while another one was running")
+			}
+		case "end":
+			running--
+		}
+	}
+	if _, err := os.Stat(filepath.Join(tmp, "web", "public", "main.wasm")); err != nil {
+		t.Errorf("output missing: %v", err)
+	}
+}
+
+// TestCompareSizes verifies fresh per-mode artifacts are reused and stale ones rebuilt
+func TestCompareSizes(t *testing.T) {
+	tmp := t.TempDir()
+	writeWasmProject(t, tmp)
+
+	w := New(&Config{
+		AppRootDir:              tmp,
+		SourceDir:               "web",
+		OutputDir:               "web/public",
+		DisableWasmExecJsOutput: true,
+		Logger:                  func(...any) {},
+	})
+	large := w.Config.BuildLargeSizeShortcut
+	artifact := filepath.Join(tmp, "web", "public", "main."+large+".wasm")
+
+	sizes, err := w.CompareSizes()
+	if err != nil || sizes[large] == 0 {
+		t.Fatalf("CompareSizes() = %v, %v: want the Large artifact built", sizes, err)
+	}
+
+	// A fresh artifact is reused as is
+	if err := os.WriteFile(artifact, []byte("fresh"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if sizes, err := w.CompareSizes(); err != nil || sizes[large] != 5 {
+		t.Errorf("CompareSizes() = %v, %v: want the fresh artifact reused", sizes, err)
+	}
+
+	// A source newer than the artifact rebuilds it
+	stale := time.Now().Add(-time.Hour)
+	if err := os.Chtimes(artifact, stale, stale); err != nil {
+		t.Fatal(err)
+	}
+	if sizes, err := w.CompareSizes(); err != nil || sizes[large] <= 5 {
+		t.Errorf("CompareSizes() = %v, %v: want the stale artifact rebuilt", sizes, err)
+	}
+}
+
+// TestAsyncBuildAcrossSetAppRootDir verifies the callback of a build started before
+// SetAppRootDir releases its own output lock, so the new project builds afterwards
+func TestAsyncBuildAcrossSetAppRootDir(t *testing.T) {
+	first := t.TempDir()
+	writeWasmProject(t, first)
+	second := t.TempDir()
+	writeWasmProject(t, second)
+
+	done := make(chan error, 2)
+	w := New(&Config{
+		AppRootDir:              first,
+		SourceDir:               "web",
+		OutputDir:               "web/public",
+		MainInputFile:           "main.go",
+		DisableWasmExecJsOutput: true,
+		Logger:                  func(...any) {},
+		Callback:                func(err error) { done <- err },
+	})
+
+	if err := w.RecompileMainWasm(); err != nil {
+		t.Fatalf("RecompileMainWasm failed: %v", err)
+	}
+	if err := w.SetAppRootDir(second); err != nil {
+		t.Fatalf("SetAppRootDir failed: %v", err)
+	}
+
+	for i := range 2 {
+		if i == 1 {
+			if err := w.RecompileMainWasm(); err != nil {
+				t.Fatalf("RecompileMainWasm after SetAppRootDir failed: %v", err)
+			}
+		}
+		select {
+		case err := <-done:
+			if i == 1 && err != nil {
+				t.Errorf("build of the new project failed: %v", err)
+			}
+		case <-time.After(2 * time.Minute):
+			t.Fatal("timed out waiting for build callback")
+		}
+	}
+
+	if _, err := os.Stat(filepath.Join(second, "web", "public", "main.wasm")); err != nil {
+		t.Errorf("new project output not written: %v", err)
+	}
+}
@@ -0,0 +1,114 @@
+package tinywasm
+
+import (
+	"bytes"
+	"compress/gzip"
+	"os"
+	"path/filepath"
+
+	"github.com/andybalholm/brotli"
+	. "github.com/cdvelop/tinystring"
+)
+
+// Extensions appended to the wasm output name for the compressed copies
+const (
+	gzipExtension   = ".gz"
+	brotliExtension = ".br"
+)
+
+// gzipLevel returns Config.GzipLevel, gzip.BestCompression when unset
+func (w *TinyWasm) gzipLevel() int {
+	if w.Config.GzipLevel == 0 {
+		return gzip.BestCompression
+	}
+	return w.Config.GzipLevel
+}
+
+// writeGzipCopy writes "<outputPath>.gz" with the given compression level
+func writeGzipCopy(outputPath string, level int, perm os.FileMode) error {
+	data, err := os.ReadFile(outputPath)
+	if err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	zw, err := gzip.NewWriterLevel(&buf, level)
+	if err != nil {
+		return Err("invalid GzipLevel:", err)
+	}
+	zw.Name = filepath.Base(outputPath)
+	if _, err := zw.Write(data); err != nil {
+		return err
+	}
+	if err := zw.Close(); err != nil {
+		return err
+	}
+
+	return writeFileAtomic(outputPath+gzipExtension, buf.Bytes(), perm)
+}
+
+// brotliQuality returns Config.BrotliQuality, brotli.BestCompression (11) when unset
+func (w *TinyWasm) brotliQuality() int {
+	if w.Config.BrotliQuality == 0 {
+		return brotli.BestCompression
+	}
+	return w.Config.BrotliQuality
+}
+
+// writeBrotliCopy writes "<outputPath>.br" with the given quality (0-11) using a
+// pure Go encoder, so no cgo or system library is needed
+func writeBrotliCopy(outputPath string, quality int, perm os.FileMode) error {
+	if quality < brotli.BestSpeed || quality > brotli.BestCompression {
+		return Errf("invalid BrotliQuality %d: must be between %d and %d", quality, brotli.BestSpeed, brotli.BestCompression)
+	}
+
+	data, err := os.ReadFile(outputPath)
+	if err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	bw := brotli.NewWriterLevel(&buf, quality)
+	if _, err := bw.Write(data); err != nil {
+		return err
+	}
+	if err := bw.Close(); err != nil {
+		return err
+	}
+
+	return writeFileAtomic(outputPath+brotliExtension, buf.Bytes(), perm)
+}
+
+// removeCompressedCopies deletes the gzip and brotli copies of outputPath, if any
+func removeCompressedCopies(outputPath string) {
+	for _, ext := range []string{gzipExtension, brotliExtension} {
+		os.Remove(outputPath + ext)
+	}
+}
+
+// writeFileAtomic writes data to a temp file next to path and renames it into place,
+// so servers never read a partially written compressed copy
+func writeFileAtomic(path string, data []byte, perm os.FileMode) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".tmp*")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := os.Chmod(tmp.Name(), perm); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return nil
+}
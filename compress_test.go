@@ -0,0 +1,121 @@
+package tinywasm
+
+import (
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/andybalholm/brotli"
+)
+
+func TestEmitGzip(t *testing.T) {
+	tmp := t.TempDir()
+	w := New(&Config{
+		AppRootDir:              tmp,
+		OutputDir:               "public",
+		EmitGzip:                true,
+		DisableWasmExecJsOutput: true,
+		Logger:                  func(...any) {},
+	})
+
+	if files := w.UnobservedFiles(); !slices.Contains(files, "main.wasm.gz") {
+		t.Errorf("UnobservedFiles() = %v, missing main.wasm.gz", files)
+	}
+
+	output := filepath.Join(tmp, "public", "main.wasm")
+	if err := os.MkdirAll(filepath.Dir(output), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	// Every successful build regenerates the copy
+	for _, content := range [][]byte{[]byte("\x00asm first build"), []byte("\x00asm second build")} {
+		if err := os.WriteFile(output, content, 0644); err != nil {
+			t.Fatal(err)
+		}
+		if err := w.afterCompile(w.builderLarge, w.Config.BuildLargeSizeShortcut, nil); err != nil {
+			t.Fatalf("afterCompile failed: %v", err)
+		}
+
+		compressed, err := os.ReadFile(output + gzipExtension)
+		if err != nil {
+			t.Fatalf("gzip copy not written: %v", err)
+		}
+		zr, err := gzip.NewReader(bytes.NewReader(compressed))
+		if err != nil {
+			t.Fatalf("invalid gzip copy: %v", err)
+		}
+		decompressed, err := io.ReadAll(zr)
+		if err != nil {
+			t.Fatalf("reading gzip copy: %v", err)
+		}
+		if !bytes.Equal(decompressed, content) {
+			t.Errorf("gzip copy = %q, want %q", decompressed, content)
+		}
+	}
+
+	w.Config.GzipLevel = 42
+	if err := w.afterCompile(w.builderLarge, w.Config.BuildLargeSizeShortcut, nil); err == nil {
+		t.Error("expected an error for an invalid GzipLevel")
+	}
+}
+
+func TestEmitBrotli(t *testing.T) {
+	tmp := t.TempDir()
+	w := New(&Config{
+		AppRootDir:              tmp,
+		OutputDir:               "public",
+		EmitBrotli:              true,
+		EmitGzip:                true,
+		DisableWasmExecJsOutput: true,
+		Logger:                  func(...any) {},
+	})
+
+	if files := w.UnobservedFiles(); !slices.Contains(files, "main.wasm.br") {
+		t.Errorf("UnobservedFiles() = %v, missing main.wasm.br", files)
+	}
+
+	output := filepath.Join(tmp, "public", "main.wasm")
+	if err := os.MkdirAll(filepath.Dir(output), 0755); err != nil {
+		t.Fatal(err)
+	}
+	content := bytes.Repeat([]byte("\x00asm brotli "), 100)
+	if err := os.WriteFile(output, content, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := w.afterCompile(w.builderLarge, w.Config.BuildLargeSizeShortcut, nil); err != nil {
+		t.Fatalf("afterCompile failed: %v", err)
+	}
+
+	compressed, err := os.ReadFile(output + brotliExtension)
+	if err != nil {
+		t.Fatalf("brotli copy not written: %v", err)
+	}
+	decompressed, err := io.ReadAll(brotli.NewReader(bytes.NewReader(compressed)))
+	if err != nil {
+		t.Fatalf("invalid brotli copy: %v", err)
+	}
+	if !bytes.Equal(decompressed, content) {
+		t.Error("brotli copy does not match the wasm output")
+	}
+
+	// A failed build must not leave copies that may not match the wasm
+	if err := w.afterCompile(w.builderLarge, w.Config.BuildLargeSizeShortcut, errors.New("build failed")); err == nil {
+		t.Fatal("expected the build error to be returned")
+	}
+	for _, ext := range []string{brotliExtension, gzipExtension} {
+		if _, err := os.Stat(output + ext); !os.IsNotExist(err) {
+			t.Errorf("%s copy should be removed after a failed build", ext)
+		}
+	}
+
+	w.Config.BrotliQuality = 12
+	if err := w.afterCompile(w.builderLarge, w.Config.BuildLargeSizeShortcut, nil); err == nil {
+		t.Error("expected an error for an out of range BrotliQuality")
+	}
+}
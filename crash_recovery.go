@@ -0,0 +1,98 @@
+package tinywasm
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// staleTempFileMargin is added to the longest compile timeout to get the age from
+// which a leftover temp output is considered orphaned (see staleTempFileAge)
+const staleTempFileMargin = 5 * time.Minute
+
+// staleTempFileAge returns the age from which a leftover temp output is considered
+// orphaned: the longest compile timeout of the modes plus staleTempFileMargin. The age
+// alone is enough, no lock file or PID check is needed: every build (in this or a
+// concurrent process with the same configuration) is killed once its timeout elapses
+// and its temp file is renamed or removed right after, so none can still own it.
+func (w *TinyWasm) staleTempFileAge() time.Duration {
+	longest := time.Duration(0)
+	for _, mode := range []string{w.Config.BuildLargeSizeShortcut, w.Config.BuildMediumSizeShortcut, w.Config.BuildSmallSizeShortcut} {
+		longest = max(longest, w.compileTimeout(mode))
+	}
+	return longest + staleTempFileMargin
+}
+
+// recoverFromCrash removes temp outputs left in the output dir by a build that
+// crashed mid-way (eg: "main_temp.wasm", "main_temp_1712345678.wasm"), which
+// would otherwise confuse the next run. Recent temp files are kept since they
+// may belong to a build that is still running.
+func (w *TinyWasm) recoverFromCrash() {
+	outputDir := filepath.Join(w.Config.AppRootDir, w.Config.OutputDir)
+
+	entries, err := os.ReadDir(outputDir)
+	if err != nil {
+		return // nothing built yet
+	}
+
+	staleAge := w.staleTempFileAge()
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !w.isTempOutputName(name) {
+			continue
+		}
+
+		info, err := entry.Info()
+		if err != nil || time.Since(info.ModTime()) < staleAge {
+			continue
+		}
+
+		if err := os.Remove(filepath.Join(outputDir, name)); err != nil {
+			w.Logger("Error removing stale temp file from a previous build:", name, err)
+			continue
+		}
+		w.Logger("Removed stale temp file from a previous build:", name)
+	}
+}
+
+// isTempOutputName reports whether name is a temp output of one of the builders (every
+// mode, its per-mode artifact of CompileAllModes and the extra entry points):
+// "<name>_temp.wasm" or "<name>_temp_<nanos>.wasm"
+func (w *TinyWasm) isTempOutputName(name string) bool {
+	if filepath.Ext(name) != ".wasm" {
+		return false
+	}
+
+	var outNames []string
+	for _, mode := range []string{w.Config.BuildLargeSizeShortcut, w.Config.BuildMediumSizeShortcut, w.Config.BuildSmallSizeShortcut} {
+		outNames = append(outNames, w.outName(mode), w.Config.OutputName+"."+mode)
+	}
+	for _, entry := range w.Config.ExtraEntryPoints {
+		outNames = append(outNames, entry.OutputName, entry.OutputName+w.Config.DebugOutputSuffix)
+	}
+
+	for _, outName := range outNames {
+		prefix := outName + "_temp"
+		if !strings.HasPrefix(name, prefix) {
+			continue
+		}
+		if rest := strings.TrimSuffix(strings.TrimPrefix(name, prefix), ".wasm"); rest == "" || isTempSuffix(rest) {
+			return true
+		}
+	}
+	return false
+}
+
+// isTempSuffix reports whether s is the "_<digits>" suffix gobuild gives temp files
+func isTempSuffix(s string) bool {
+	if len(s) < 2 || s[0] != '_' {
+		return false
+	}
+	for _, c := range s[1:] {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
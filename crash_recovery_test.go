@@ -0,0 +1,79 @@
+package tinywasm
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestRecoverFromCrashRemovesStaleTempFiles(t *testing.T) {
+	tmp := t.TempDir()
+	outputDir := filepath.Join(tmp, "public")
+	if err := os.MkdirAll(outputDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	old := time.Now().Add(-time.Hour)
+	files := map[string]bool{ // name -> stale
+		"main_temp.wasm":              true,
+		"main_temp_1712345678.wasm":   true,
+		"main_temp_1799999999.wasm":   false, // recent: may belong to a running build
+		"main.wasm":                   true,
+		"main_template.wasm":          true,
+		"app_temp.wasm":               true, // Small mode with its own output name
+		"main.S_temp_1712345678.wasm": true,
+		"worker_temp.wasm":            true,
+		"worker.debug_temp.wasm":      true,
+	}
+	for name, stale := range files {
+		p := filepath.Join(outputDir, name)
+		if err := os.WriteFile(p, []byte("x"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if stale {
+			if err := os.Chtimes(p, old, old); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	New(&Config{
+		AppRootDir:              tmp,
+		OutputDir:               "public",
+		DebugOutputSuffix:       ".debug",
+		OutputNamePerMode:       map[string]string{"S": "app"},
+		ExtraEntryPoints:        []EntryPoint{{InputFile: "worker/main.go", OutputName: "worker"}},
+		DisableWasmExecJsOutput: true,
+		Logger:                  func(...any) {},
+	})
+
+	for name, wantExists := range map[string]bool{
+		"main_temp.wasm":              false,
+		"main_temp_1712345678.wasm":   false,
+		"main_temp_1799999999.wasm":   true,
+		"main.wasm":                   true,
+		"main_template.wasm":          true,
+		"app_temp.wasm":               false,
+		"main.S_temp_1712345678.wasm": false,
+		"worker_temp.wasm":            false,
+		"worker.debug_temp.wasm":      false,
+	} {
+		_, err := os.Stat(filepath.Join(outputDir, name))
+		if exists := err == nil; exists != wantExists {
+			t.Errorf("%s: exists = %v, want %v", name, exists, wantExists)
+		}
+	}
+}
+
+func TestStaleTempFileAgeFollowsTimeouts(t *testing.T) {
+	w := New(&Config{AppRootDir: t.TempDir(), DisableWasmExecJsOutput: true, Logger: func(...any) {}})
+	if got, want := w.staleTempFileAge(), 120*time.Second+staleTempFileMargin; got != want {
+		t.Errorf("staleTempFileAge() = %v, want the default Small timeout plus margin %v", got, want)
+	}
+
+	w.Config.TimeoutMedium = 2 * time.Hour
+	if got, want := w.staleTempFileAge(), 2*time.Hour+staleTempFileMargin; got != want {
+		t.Errorf("staleTempFileAge() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,52 @@
+package tinywasm
+
+import (
+	"slices"
+	"time"
+)
+
+// debouncedChanges collects the files changed during Config.DebounceInterval, guarded by
+// TinyWasm.buildMu
+type debouncedChanges struct {
+	timer *time.Timer
+	paths []string // changed files since the last debounced build, in event order
+	mode  string   // active mode of the latest change, built by the flush
+}
+
+// debounceCompile records a change of filePath and restarts the debounce timer: the
+// build runs once no event arrived for Config.DebounceInterval
+func (w *TinyWasm) debounceCompile(filePath string) {
+	mode := w.Value()
+
+	w.buildMu.Lock()
+	defer w.buildMu.Unlock()
+
+	w.debounce.mode = mode
+
+	if !slices.Contains(w.debounce.paths, filePath) {
+		w.debounce.paths = append(w.debounce.paths, filePath)
+	}
+	if w.debounce.timer == nil {
+		w.debounce.timer = time.AfterFunc(w.Config.DebounceInterval, w.flushDebouncedChanges)
+	} else {
+		w.debounce.timer.Reset(w.Config.DebounceInterval)
+	}
+}
+
+// flushDebouncedChanges compiles the changes collected by debounceCompile at once (see
+// compileForChanges): the main program is built a single time for all of them, with the
+// mode recorded by debounceCompile since the timer goroutine never reads the mode itself
+func (w *TinyWasm) flushDebouncedChanges() {
+	w.buildMu.Lock()
+	paths, mode := w.debounce.paths, w.debounce.mode
+	w.debounce.paths = nil
+	w.buildMu.Unlock()
+
+	if len(paths) == 0 {
+		return
+	}
+
+	if err := w.compileForChanges(mode, paths...); err != nil {
+		w.Logger(err)
+	}
+}
@@ -0,0 +1,128 @@
+package tinywasm
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// TestDebounceInterval verifies a burst of events compiles once after the window
+func TestDebounceInterval(t *testing.T) {
+	tmp := t.TempDir()
+	writeWasmProject(t, tmp)
+
+	w := New(&Config{
+		AppRootDir:              tmp,
+		SourceDir:               "web",
+		OutputDir:               "web/public",
+		DisableWasmExecJsOutput: true,
+		ForceRecompile:          true,
+		DebounceInterval:        50 * time.Millisecond,
+		Logger:                  func(...any) {},
+	})
+	events, cancel := w.Events()
+	defer cancel()
+
+	mainPath := filepath.Join(tmp, "web", "main.go")
+	for range 3 {
+		if err := w.NewFileEvent("main.go", ".go", mainPath, "write"); err != nil {
+			t.Fatalf("NewFileEvent failed: %v", err)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if w.IsBuilding() || len(events) > 0 {
+		t.Fatal("no build should start within the debounce window")
+	}
+
+	starts := 0
+	timeout := time.After(30 * time.Second)
+	for done := false; !done; {
+		select {
+		case e := <-events:
+			switch e.Type {
+			case "start":
+				starts++
+			case "end":
+				if e.Error != "" {
+					t.Fatalf("debounced build failed: %s", e.Error)
+				}
+				done = true
+			}
+		case <-timeout:
+			t.Fatal("debounced build never ran")
+		}
+	}
+
+	time.Sleep(100 * time.Millisecond)
+	if starts != 1 || len(events) > 0 {
+		t.Errorf("builds started = %d (pending events %d), want a single build", starts, len(events))
+	}
+}
+
+// TestDebouncedChangesCheckEveryPath verifies a changed file is built even when the first
+// collected path is unchanged since the last build, and the main program builds once
+func TestDebouncedChangesCheckEveryPath(t *testing.T) {
+	tmp := t.TempDir()
+	writeWasmProject(t, tmp)
+
+	w := New(&Config{
+		AppRootDir:              tmp,
+		SourceDir:               "web",
+		OutputDir:               "web/public",
+		DisableWasmExecJsOutput: true,
+		DebounceInterval:        time.Hour, // flushed by hand
+		Logger:                  func(...any) {},
+	})
+
+	mainPath := filepath.Join(tmp, "web", "main.go")
+	if err := w.compileForChanges(w.Value(), mainPath); err != nil {
+		t.Fatalf("initial build failed: %v", err)
+	}
+
+	otherPath := filepath.Join(tmp, "web", "other.go")
+	if err := os.WriteFile(otherPath, []byte("package main\n\nfunc helper() {}\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	for _, p := range []string{mainPath, otherPath, otherPath} {
+		if err := w.NewFileEvent(filepath.Base(p), ".go", p, "write"); err != nil {
+			t.Fatalf("NewFileEvent failed: %v", err)
+		}
+	}
+	w.flushDebouncedChanges()
+
+	if builds := len(w.BuildHistory(0)); builds != 2 {
+		t.Errorf("builds = %d, want the initial build plus a single build for other.go", builds)
+	}
+}
+
+// TestDebounceConcurrentChange verifies a debounced build firing while Change runs on the
+// caller's goroutine doesn't race on the active mode (run with -race)
+func TestDebounceConcurrentChange(t *testing.T) {
+	tmp := t.TempDir()
+	w := New(&Config{
+		AppRootDir:              tmp,
+		SourceDir:               "web",
+		OutputDir:               "web/public",
+		DisableWasmExecJsOutput: true,
+		ForceRecompile:          true,
+		DebounceInterval:        20 * time.Millisecond,
+		Logger:                  func(...any) {},
+	})
+
+	// No main file: Change returns right after switching, the flush fails fast
+	if err := w.NewFileEvent("util.go", ".go", filepath.Join(tmp, "web", "util.go"), "write"); err != nil {
+		t.Fatalf("NewFileEvent failed: %v", err)
+	}
+	progress := make(chan string, 1)
+	for deadline := time.Now().Add(100 * time.Millisecond); time.Now().Before(deadline); {
+		w.Change(w.Config.BuildLargeSizeShortcut, progress)
+		<-progress
+	}
+
+	for deadline := time.Now().Add(30 * time.Second); w.IsBuilding(); time.Sleep(10 * time.Millisecond) {
+		if time.Now().After(deadline) {
+			t.Fatal("debounced build never finished")
+		}
+	}
+}
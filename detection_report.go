@@ -0,0 +1,22 @@
+package tinywasm
+
+// Detection sources reported by DetectionReport
+const (
+	DetectionSourceWasmExecJs = "wasm_exec.js" // runtime signatures of an existing wasm_exec.js
+	DetectionSourceGoFiles    = "go-files"     // main input file or *.wasm.go files
+	DetectionSourceNone       = "none"         // no WASM project found
+)
+
+// DetectionReport describes how New detected the project
+type DetectionReport struct {
+	IsWasmProject bool
+	Source        string // DetectionSourceWasmExecJs, DetectionSourceGoFiles or DetectionSourceNone
+	Compiler      string // "go" or "tinygo", inferred for the detected project
+	Mode          string // mode shortcut active after detection, eg: restored from the wasm_exec.js header
+}
+
+// DetectionReport returns why the project was (or was not) detected as a WASM project
+// when New ran, and which compiler and mode were inferred
+func (w *TinyWasm) DetectionReport() DetectionReport {
+	return w.detection
+}
@@ -0,0 +1,92 @@
+package tinywasm
+
+import (
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	. "github.com/cdvelop/tinystring"
+)
+
+// gobuildFailurePrefix starts the error gobuild returns for a failed compiler run,
+// followed by the exit status and the combined compiler output
+const gobuildFailurePrefix = "compileSync build failed: "
+
+// diagnosticPathPattern matches a relative source path at the start of a compiler
+// diagnostic line, eg: "../main.go" in "../main.go:4:2: declared and not used: x"
+var diagnosticPathPattern = regexp.MustCompile(`(?m)^([^\s/:][^\s:]*\.go):(\d+)`)
+
+// Diagnostic is a compiler message about a source position, eg: an editor squiggle
+type Diagnostic struct {
+	File     string // absolute path of the source file
+	Line     int
+	Column   int    // 0 when the compiler reports no column
+	Message  string // eg: "declared and not used: x"
+	Severity string // "error" or "warning"
+}
+
+// diagnosticLinePattern matches a "file:line:col: message" compiler line, col optional
+var diagnosticLinePattern = regexp.MustCompile(`(?m)^(\S+?\.go):(\d+)(?::(\d+))?:\s*(.*?)\s*$`)
+
+// LastDiagnostics returns the diagnostics of the last finished build, parsed from the
+// compiler output: empty when it succeeded or failed without source positions (eg: a
+// timeout or a post-build step)
+func (w *TinyWasm) LastDiagnostics() []Diagnostic {
+	w.buildMu.Lock()
+	defer w.buildMu.Unlock()
+	return append([]Diagnostic{}, w.diagnostics...)
+}
+
+// recordDiagnostics stores the diagnostics of a finished build result for LastDiagnostics
+func (w *TinyWasm) recordDiagnostics(err error) {
+	var diagnostics []Diagnostic
+	if err != nil {
+		diagnostics = parseDiagnostics(err.Error())
+	}
+	w.buildMu.Lock()
+	w.diagnostics = diagnostics
+	w.buildMu.Unlock()
+}
+
+// parseDiagnostics returns the "file:line:col: message" lines of compiler output
+func parseDiagnostics(output string) []Diagnostic {
+	var diagnostics []Diagnostic
+	for _, m := range diagnosticLinePattern.FindAllStringSubmatch(output, -1) {
+		d := Diagnostic{File: m[1], Message: m[4], Severity: "error"}
+		d.Line, _ = strconv.Atoi(m[2])
+		d.Column, _ = strconv.Atoi(m[3])
+		if rest, ok := strings.CutPrefix(d.Message, "warning:"); ok {
+			d.Message, d.Severity = strings.TrimSpace(rest), "warning"
+		}
+		diagnostics = append(diagnostics, d)
+	}
+	return diagnostics
+}
+
+// compilerDiagnostics returns the error of a failed compiler run with its output on
+// lines of its own and the source paths made absolute (builds run from OutputDir), so
+// editors can parse the "file:line:col" diagnostics. Other errors are returned as is.
+func (w *TinyWasm) compilerDiagnostics(err error) error {
+	msg := err.Error()
+	if !strings.HasPrefix(msg, gobuildFailurePrefix) {
+		return err
+	}
+	msg = strings.TrimPrefix(msg, gobuildFailurePrefix)
+
+	status, output := msg, ""
+	if fields := strings.SplitN(msg, " ", 4); len(fields) == 4 && fields[0] == "exit" && fields[1] == "status" {
+		status, output = strings.Join(fields[:3], " "), fields[3] // eg: "exit status 1"
+	}
+
+	outputDir := filepath.Join(w.Config.AppRootDir, w.Config.OutputDir)
+	output = diagnosticPathPattern.ReplaceAllStringFunc(strings.TrimSpace(output), func(match string) string {
+		file, line, _ := strings.Cut(match, ":")
+		return filepath.Join(outputDir, file) + ":" + line
+	})
+
+	if output == "" {
+		return Err("build failed:", status)
+	}
+	return Err("build failed:", status+"\n"+output)
+}
@@ -0,0 +1,67 @@
+package tinywasm
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestParseDiagnostics(t *testing.T) {
+	output := "build failed: exit status 1\n# command-line-arguments\n" +
+		"/app/web/main.go:4:2: declared and not used: x\n" +
+		"/app/web/util.go:10: warning: unreachable code\n"
+
+	got := parseDiagnostics(output)
+	want := []Diagnostic{
+		{File: "/app/web/main.go", Line: 4, Column: 2, Message: "declared and not used: x", Severity: "error"},
+		{File: "/app/web/util.go", Line: 10, Message: "unreachable code", Severity: "warning"},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("parseDiagnostics() = %+v, want %+v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("diagnostic %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
+
+// TestLastDiagnostics verifies the diagnostics follow the last build result
+func TestLastDiagnostics(t *testing.T) {
+	tmp := t.TempDir()
+	writeWasmProject(t, tmp)
+	mainPath := filepath.Join(tmp, "web", "main.go")
+	valid, err := os.ReadFile(mainPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(mainPath, []byte("package main\n\nfunc main() {\n\tx := 1\n}\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	w := New(&Config{
+		AppRootDir:              tmp,
+		SourceDir:               "web",
+		OutputDir:               "web/public",
+		DisableWasmExecJsOutput: true,
+		Logger:                  func(...any) {},
+	})
+
+	if err := w.NewFileEvent("main.go", ".go", mainPath, "write"); err == nil {
+		t.Fatal("expected a compile error")
+	}
+	diagnostics := w.LastDiagnostics()
+	if len(diagnostics) != 1 || diagnostics[0].File != mainPath || diagnostics[0].Line != 4 || diagnostics[0].Column != 2 {
+		t.Errorf("LastDiagnostics() = %+v, want main.go:4:2", diagnostics)
+	}
+
+	if err := os.WriteFile(mainPath, valid, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.NewFileEvent("main.go", ".go", mainPath, "write"); err != nil {
+		t.Fatalf("build failed: %v", err)
+	}
+	if diagnostics := w.LastDiagnostics(); diagnostics == nil || len(diagnostics) != 0 {
+		t.Errorf("LastDiagnostics() = %#v, want an empty slice after a successful build", diagnostics)
+	}
+}
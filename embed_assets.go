@@ -0,0 +1,148 @@
+package tinywasm
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	. "github.com/cdvelop/tinystring"
+)
+
+// EmbeddedAssetPaths returns the files referenced by //go:embed directives in the
+// main package (the .go files next to MainInputFile). Directory patterns are expanded
+// recursively following go:embed rules (files starting with "." or "_" are skipped
+// unless the pattern uses the "all:" prefix). Watchers can use this set to observe
+// embedded assets; NewFileEvent treats changes to them as compile triggers.
+func (w *TinyWasm) EmbeddedAssetPaths() ([]string, error) {
+	sourceDir := filepath.Join(w.Config.AppRootDir, w.Config.SourceDir)
+
+	patterns, err := embedPatterns(sourceDir)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]bool)
+	var assets []string
+	add := func(p string) {
+		if !seen[p] {
+			seen[p] = true
+			assets = append(assets, p)
+		}
+	}
+
+	for _, pattern := range patterns {
+		includeHidden := false
+		if HasPrefix(pattern, "all:") {
+			includeHidden = true
+			pattern = strings.TrimPrefix(pattern, "all:")
+		}
+
+		matches, err := filepath.Glob(filepath.Join(sourceDir, filepath.FromSlash(pattern)))
+		if err != nil {
+			return nil, Err("invalid go:embed pattern", pattern, ":", err)
+		}
+
+		for _, match := range matches {
+			info, err := os.Stat(match)
+			if err != nil {
+				continue
+			}
+			if !info.IsDir() {
+				add(match)
+				continue
+			}
+			filepath.Walk(match, func(p string, fi os.FileInfo, err error) error {
+				if err != nil {
+					return nil
+				}
+				name := fi.Name()
+				if p != match && !includeHidden && (HasPrefix(name, ".") || HasPrefix(name, "_")) {
+					if fi.IsDir() {
+						return filepath.SkipDir
+					}
+					return nil
+				}
+				if !fi.IsDir() {
+					add(p)
+				}
+				return nil
+			})
+		}
+	}
+
+	sort.Strings(assets)
+	return assets, nil
+}
+
+// embedPatterns collects the patterns of every //go:embed directive in the
+// non-test .go files of sourceDir
+func embedPatterns(sourceDir string) ([]string, error) {
+	entries, err := os.ReadDir(sourceDir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var patterns []string
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || filepath.Ext(name) != ".go" || HasSuffix(name, "_test.go") {
+			continue
+		}
+
+		f, err := os.Open(filepath.Join(sourceDir, name))
+		if err != nil {
+			return nil, err
+		}
+
+		scanner := bufio.NewScanner(f)
+		for scanner.Scan() {
+			line := strings.TrimSpace(scanner.Text())
+			if !HasPrefix(line, "//go:embed ") {
+				continue
+			}
+			for _, p := range strings.Fields(strings.TrimPrefix(line, "//go:embed ")) {
+				patterns = append(patterns, strings.Trim(p, "\"`"))
+			}
+		}
+		f.Close()
+	}
+
+	return patterns, nil
+}
+
+// isEmbeddedAsset reports whether filePath is one of the tracked go:embed assets.
+// The tracked set is cached and refreshed on "create" events so newly added files
+// matching an existing pattern are picked up.
+func (w *TinyWasm) isEmbeddedAsset(filePath, event string) bool {
+	if w.embeddedAssets == nil || event == "create" {
+		w.refreshEmbeddedAssets()
+	}
+
+	absPath, err := filepath.Abs(filePath)
+	if err != nil {
+		return false
+	}
+	return w.embeddedAssets[absPath]
+}
+
+// refreshEmbeddedAssets rebuilds the cached set of go:embed assets
+func (w *TinyWasm) refreshEmbeddedAssets() {
+	w.embeddedAssets = make(map[string]bool)
+
+	assets, err := w.EmbeddedAssetPaths()
+	if err != nil {
+		w.Logger("Error reading go:embed directives:", err)
+		return
+	}
+
+	for _, asset := range assets {
+		if absPath, err := filepath.Abs(asset); err == nil {
+			w.embeddedAssets[absPath] = true
+		}
+	}
+}
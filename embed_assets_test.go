@@ -0,0 +1,77 @@
+package tinywasm
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestEmbeddedAssetPaths(t *testing.T) {
+	tmp := t.TempDir()
+	sourceDir := filepath.Join(tmp, "web")
+
+	files := map[string]string{
+		"main.go": "package main\n\nimport \"embed\"\n\n" +
+			"//go:embed templates/*.html static\nvar assets embed.FS\n\nfunc main() {}\n",
+		"templates/index.html":  "<h1>index</h1>",
+		"templates/notes.txt":   "not embedded",
+		"static/css/style.css":  "body{}",
+		"static/_draft.css":     "hidden",
+		"static/.hidden/a.css":  "hidden",
+		"unrelated/ignored.css": "not embedded",
+	}
+	for name, content := range files {
+		p := filepath.Join(sourceDir, name)
+		if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+			t.Fatalf("creating dir: %v", err)
+		}
+		if err := os.WriteFile(p, []byte(content), 0644); err != nil {
+			t.Fatalf("writing %s: %v", name, err)
+		}
+	}
+
+	w := New(&Config{
+		AppRootDir:              tmp,
+		SourceDir:               "web",
+		OutputDir:               "public",
+		DisableWasmExecJsOutput: true,
+		Logger:                  func(...any) {},
+	})
+
+	assets, err := w.EmbeddedAssetPaths()
+	if err != nil {
+		t.Fatalf("EmbeddedAssetPaths failed: %v", err)
+	}
+
+	expected := []string{
+		filepath.Join(sourceDir, "static", "css", "style.css"),
+		filepath.Join(sourceDir, "templates", "index.html"),
+	}
+	if !slices.Equal(assets, expected) {
+		t.Errorf("EmbeddedAssetPaths() = %v, want %v", assets, expected)
+	}
+
+	extensions := w.SupportedExtensions()
+	for _, ext := range []string{".go", ".html", ".css"} {
+		if !slices.Contains(extensions, ext) {
+			t.Errorf("SupportedExtensions() = %v, missing %s", extensions, ext)
+		}
+	}
+
+	if !w.isEmbeddedAsset(filepath.Join(sourceDir, "templates", "index.html"), "write") {
+		t.Error("expected templates/index.html to be tracked as embedded asset")
+	}
+	if w.isEmbeddedAsset(filepath.Join(sourceDir, "unrelated", "ignored.css"), "write") {
+		t.Error("unrelated/ignored.css should not be tracked")
+	}
+
+	// New files matching an existing pattern are picked up on create
+	newPage := filepath.Join(sourceDir, "templates", "about.html")
+	if err := os.WriteFile(newPage, []byte("<h1>about</h1>"), 0644); err != nil {
+		t.Fatalf("writing new page: %v", err)
+	}
+	if !w.isEmbeddedAsset(newPage, "create") {
+		t.Error("expected newly created templates/about.html to be tracked")
+	}
+}
@@ -0,0 +1,100 @@
+package tinywasm
+
+import (
+	"path"
+	"path/filepath"
+	"slices"
+
+	"github.com/cdvelop/gobuild"
+	. "github.com/cdvelop/tinystring"
+)
+
+// EntryPoint is an additional wasm program compiled next to the main one, eg: a web
+// worker (see Config.ExtraEntryPoints)
+type EntryPoint struct {
+	InputFile  string // main file relative to SourceDir, eg: "worker.wasm.go"
+	OutputName string // output name without extension, eg: "worker" writes worker.wasm
+}
+
+// entryBuilders holds the builders of one extra entry point, one per mode
+type entryBuilders struct {
+	entry    EntryPoint
+	builders map[string]*gobuild.GoBuild
+}
+
+// entryBuilderInit configures the builders of Config.ExtraEntryPoints: the same compiler
+// setup as the main builder of each mode, with the entry's input and output name
+func (w *TinyWasm) entryBuilderInit() {
+	w.entries = nil
+	for _, entry := range w.Config.ExtraEntryPoints {
+		eb := &entryBuilders{entry: entry, builders: make(map[string]*gobuild.GoBuild, 3)}
+		for _, mode := range []string{w.Config.BuildLargeSizeShortcut, w.Config.BuildMediumSizeShortcut, w.Config.BuildSmallSizeShortcut} {
+			config := w.builderConfig(mode)
+			config.MainInputFileRelativePath = path.Join(w.AppRootDir, w.Config.SourceDir, entry.InputFile)
+			config.OutName = entry.OutputName
+			if mode == w.Config.BuildMediumSizeShortcut {
+				config.OutName += w.Config.DebugOutputSuffix
+			}
+			eb.builders[mode] = w.newBuilder(config, mode)
+		}
+		w.entries = append(w.entries, eb)
+	}
+}
+
+// entryForFile returns the extra entry point whose input file is filePath, nil if none
+func (w *TinyWasm) entryForFile(filePath string) *entryBuilders {
+	abs, err := filepath.Abs(filePath)
+	if err != nil {
+		return nil
+	}
+	for _, eb := range w.entries {
+		input, err := filepath.Abs(filepath.Join(w.Config.AppRootDir, w.Config.SourceDir, eb.entry.InputFile))
+		if err == nil && abs == input {
+			return eb
+		}
+	}
+	return nil
+}
+
+// isEntryBuilder reports whether b builds an extra entry point rather than the main program
+func (w *TinyWasm) isEntryBuilder(b *gobuild.GoBuild) bool {
+	for _, eb := range w.entries {
+		for _, builder := range eb.builders {
+			if builder == b {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// compileEntries compiles every extra entry point in mode, returning the first error
+func (w *TinyWasm) compileEntries(mode string) error {
+	var firstErr error
+	for _, eb := range w.entries {
+		if err := w.compileWith(eb.builders[mode], mode); err != nil && firstErr == nil {
+			firstErr = Err("compiling entry point", eb.entry.OutputName+":", err.Error())
+		}
+	}
+	return firstErr
+}
+
+// validateEntryPoints checks every extra entry point has an input file and an output
+// name of its own
+func (w *TinyWasm) validateEntryPoints() error {
+	var names []string
+	for _, mode := range []string{w.Config.BuildLargeSizeShortcut, w.Config.BuildMediumSizeShortcut, w.Config.BuildSmallSizeShortcut} {
+		names = append(names, w.outName(mode))
+	}
+
+	for _, entry := range w.Config.ExtraEntryPoints {
+		if entry.InputFile == "" || entry.OutputName == "" {
+			return Err("extra entry point needs an InputFile and an OutputName:", Fmt("%+v", entry))
+		}
+		if slices.Contains(names, entry.OutputName) {
+			return Err("extra entry point", entry.InputFile, "output name", entry.OutputName, "is already in use")
+		}
+		names = append(names, entry.OutputName)
+	}
+	return nil
+}
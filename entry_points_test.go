@@ -0,0 +1,93 @@
+package tinywasm
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// TestExtraEntryPoints verifies a change to an entry's input only rebuilds that entry
+func TestExtraEntryPoints(t *testing.T) {
+	tmp := t.TempDir()
+	writeWasmProject(t, tmp)
+
+	workerPath := filepath.Join(tmp, "web", "worker", "main.go")
+	if err := os.MkdirAll(filepath.Dir(workerPath), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(workerPath, []byte("package main\n\nfunc main() {\n\tprintln(\"worker\")\n}\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	w := New(&Config{
+		AppRootDir:              tmp,
+		SourceDir:               "web",
+		OutputDir:               "web/public",
+		MainInputFile:           "main.go",
+		DisableWasmExecJsOutput: true,
+		ExtraEntryPoints:        []EntryPoint{{InputFile: "worker/main.go", OutputName: "worker"}},
+		Logger:                  func(...any) {},
+	})
+	if err := w.Validate(); err != nil {
+		t.Fatalf("Validate failed: %v", err)
+	}
+
+	mainOutput := filepath.Join(tmp, "web", "public", "main.wasm")
+	workerOutput := filepath.Join(tmp, "web", "public", "worker.wasm")
+
+	if err := w.NewFileEvent("main.go", ".go", workerPath, "write"); err != nil {
+		t.Fatalf("worker event failed: %v", err)
+	}
+	if _, err := os.Stat(workerOutput); err != nil {
+		t.Fatalf("worker entry should be compiled: %v", err)
+	}
+	if _, err := os.Stat(mainOutput); err == nil {
+		t.Error("main program should not be compiled for a change to the worker input")
+	}
+
+	before, _ := os.Stat(workerOutput)
+	time.Sleep(10 * time.Millisecond)
+	if err := w.NewFileEvent("main.go", ".go", filepath.Join(tmp, "web", "main.go"), "write"); err != nil {
+		t.Fatalf("main event failed: %v", err)
+	}
+	if _, err := os.Stat(mainOutput); err != nil {
+		t.Fatalf("main program should be compiled: %v", err)
+	}
+	if after, _ := os.Stat(workerOutput); !after.ModTime().Equal(before.ModTime()) {
+		t.Error("worker entry should not be rebuilt for a change to the main input")
+	}
+
+	w.Config.ExtraEntryPoints = append(w.Config.ExtraEntryPoints, EntryPoint{InputFile: "other.go", OutputName: "main"})
+	if err := w.Validate(); err == nil {
+		t.Error("Validate should reject an entry reusing the main output name")
+	}
+}
+
+// TestEntryForFileResolvesPaths verifies entry inputs match whichever way the paths are given
+func TestEntryForFileResolvesPaths(t *testing.T) {
+	tmp := t.TempDir()
+	t.Chdir(tmp)
+
+	w := New(&Config{
+		AppRootDir:              tmp,
+		SourceDir:               "web",
+		ExtraEntryPoints:        []EntryPoint{{InputFile: "worker/main.go", OutputName: "worker"}},
+		DisableWasmExecJsOutput: true,
+		Logger:                  func(...any) {},
+	})
+	w.Config.AppRootDir = "." // relative root, eg: set by the embedding tool after New
+
+	for _, filePath := range []string{
+		filepath.Join(tmp, "web", "worker", "main.go"),
+		filepath.Join("web", "worker", "main.go"),
+		filepath.Join(tmp, "web", "other", "..", "worker", "main.go"),
+	} {
+		if w.entryForFile(filePath) == nil {
+			t.Errorf("entryForFile(%s) = nil, want the worker entry", filePath)
+		}
+	}
+	if w.entryForFile(filepath.Join(tmp, "web", "main.go")) != nil {
+		t.Error("the main input is not an extra entry point")
+	}
+}
@@ -0,0 +1,67 @@
+package tinywasm
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	. "github.com/cdvelop/tinystring"
+)
+
+// EstimateSize compiles mode into a scratch directory under the temp dir (Config.TempDir,
+// the OS temp dir by default) and returns the size of the resulting wasm without ever
+// writing to OutputDir. The scratch output is removed afterwards and the active mode and
+// builders are left untouched, which makes it usable for size analysis where the real
+// output dir must not be written.
+func (w *TinyWasm) EstimateSize(mode string) (int64, error) {
+	if err := w.validateMode(mode); err != nil {
+		return 0, err
+	}
+	mode = Convert(mode).ToUpper().String()
+
+	scratchDir, err := os.MkdirTemp(w.tempDir(), "tinywasm-estimate-*")
+	if err != nil {
+		return 0, Err("creating scratch dir:", err)
+	}
+	defer os.RemoveAll(scratchDir)
+
+	scratchOutput := filepath.Join(scratchDir, w.outName(mode)+".wasm")
+
+	rootDir, err := filepath.Abs(w.Config.AppRootDir)
+	if err != nil {
+		return 0, err
+	}
+
+	// Same arguments as the real build, only the output path differs. The main
+	// input (last argument) is made absolute as the build runs from rootDir.
+	args := w.builderForMode(mode).BuildArguments()
+	if i := slices.Index(args, "-o"); i >= 0 && i+1 < len(args) {
+		args[i+1] = scratchOutput
+	}
+	if mainInput, err := filepath.Abs(args[len(args)-1]); err == nil {
+		args[len(args)-1] = mainInput
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), w.compileTimeout(mode))
+	defer cancel()
+
+	config := w.builderConfig(mode)
+	cmd := exec.CommandContext(ctx, config.Command, args...)
+	cmd.Dir = rootDir
+	if len(config.Env) > 0 {
+		cmd.Env = append(os.Environ(), config.Env...)
+	}
+
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return 0, Err("estimating size of mode", mode, ":", err, strings.TrimSpace(string(output)))
+	}
+
+	info, err := os.Stat(scratchOutput)
+	if err != nil {
+		return 0, Err("reading scratch output:", err)
+	}
+	return info.Size(), nil
+}
@@ -0,0 +1,44 @@
+package tinywasm
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestEstimateSizeDoesNotTouchOutputDir(t *testing.T) {
+	tmp := t.TempDir()
+	writeWasmProject(t, tmp)
+
+	w := New(&Config{
+		AppRootDir:              tmp,
+		SourceDir:               "web",
+		OutputDir:               "web/public",
+		MainInputFile:           "main.go",
+		DisableWasmExecJsOutput: true,
+		Logger:                  func(...any) {},
+	})
+
+	size, err := w.EstimateSize("l")
+	if err != nil {
+		t.Fatalf("EstimateSize failed: %v", err)
+	}
+	if size == 0 {
+		t.Error("expected a non-zero size estimate")
+	}
+
+	entries, err := os.ReadDir(filepath.Join(tmp, "web", "public"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 0 {
+		t.Errorf("OutputDir should stay untouched, found %v", entries)
+	}
+	if w.Value() != w.Config.BuildLargeSizeShortcut {
+		t.Errorf("active mode changed to %s", w.Value())
+	}
+
+	if _, err := w.EstimateSize("X"); err == nil {
+		t.Error("expected error for unknown mode")
+	}
+}
@@ -0,0 +1,65 @@
+package tinywasm
+
+// eventBufferSize is the per-subscriber buffer of Events; slow subscribers miss
+// events instead of blocking builds
+const eventBufferSize = 32
+
+// BuildEvent describes a step of a build, see Events
+type BuildEvent struct {
+	Type       string `json:"type"`                  // "start", "progress" or "end"
+	Mode       string `json:"mode"`                  // mode shortcut eg: "L"
+	Message    string `json:"message,omitempty"`     // progress description
+	DurationMs int64  `json:"duration_ms,omitempty"` // end: build time in milliseconds
+	Size       int64  `json:"size,omitempty"`        // end: wasm output size in bytes
+	Error      string `json:"error,omitempty"`       // end: build error, empty on success
+}
+
+// Events subscribes to build events: "start" when a build begins, "progress" for
+// post-build steps and "end" with its result. Events are dropped for a subscriber
+// whose buffer is full, so builds never wait on consumers. Call cancel to
+// unsubscribe; the channel is closed afterwards.
+func (w *TinyWasm) Events() (events <-chan BuildEvent, cancel func()) {
+	ch := make(chan BuildEvent, eventBufferSize)
+
+	w.eventsMu.Lock()
+	if w.eventSubs == nil {
+		w.eventSubs = make(map[chan BuildEvent]struct{})
+	}
+	w.eventSubs[ch] = struct{}{}
+	w.eventsMu.Unlock()
+
+	return ch, func() {
+		w.eventsMu.Lock()
+		defer w.eventsMu.Unlock()
+		if _, ok := w.eventSubs[ch]; ok {
+			delete(w.eventSubs, ch)
+			close(ch)
+		}
+	}
+}
+
+// publishEvent sends e to every subscriber without blocking
+func (w *TinyWasm) publishEvent(e BuildEvent) {
+	w.eventsMu.Lock()
+	defer w.eventsMu.Unlock()
+	for ch := range w.eventSubs {
+		select {
+		case ch <- e:
+		default:
+		}
+	}
+}
+
+// endEvent converts a finished build result into its "end" event
+func endEvent(result BuildResult) BuildEvent {
+	e := BuildEvent{
+		Type:       "end",
+		Mode:       result.Mode,
+		DurationMs: result.Duration.Milliseconds(),
+		Size:       result.Size,
+	}
+	if result.Err != nil {
+		e.Error = result.Err.Error()
+	}
+	return e
+}
@@ -0,0 +1,43 @@
+package tinywasm
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestEvents(t *testing.T) {
+	w := New(&Config{AppRootDir: t.TempDir(), Logger: func(...any) {}})
+
+	events, cancel := w.Events()
+
+	w.buildStarted("L")
+	w.buildFinished("L", errors.New("syntax error"))
+
+	expected := []BuildEvent{
+		{Type: "start", Mode: "L"},
+		{Type: "end", Mode: "L", Error: "syntax error"},
+	}
+	for _, want := range expected {
+		select {
+		case got := <-events:
+			got.DurationMs = 0
+			if got != want {
+				t.Errorf("event = %+v, want %+v", got, want)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("timed out waiting for %s event", want.Type)
+		}
+	}
+
+	cancel()
+	if _, open := <-events; open {
+		t.Error("channel should be closed after cancel")
+	}
+	cancel() // idempotent
+
+	// Publishing without subscribers or to a full buffer never blocks
+	for range eventBufferSize + 1 {
+		w.buildStarted("S")
+	}
+}
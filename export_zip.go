@@ -0,0 +1,74 @@
+package tinywasm
+
+import (
+	"archive/zip"
+	"os"
+	"path/filepath"
+
+	. "github.com/cdvelop/tinystring"
+)
+
+// ExportZip builds the current mode and writes a self-contained zip to destPath with
+// the wasm output, wasm_exec.js (including the initialization code) and a working
+// index.html, ready to be extracted into a web root to share a quick demo.
+func (w *TinyWasm) ExportZip(destPath string) error {
+	mode := w.Value()
+
+	outputPath, err := w.buildSync(mode, w.outName(mode))
+	if err != nil {
+		return Err("building for zip export:", err)
+	}
+
+	wasm, err := os.ReadFile(outputPath)
+	if err != nil {
+		return Err("reading wasm output:", err)
+	}
+
+	// A successful build proves this is a wasm project: assume it only while generating
+	// the zip's wasm_exec.js, detection of the instance is left as it was
+	wasmProject := w.wasmProject
+	w.wasmProject = true
+	js, err := w.JavascriptForInitializing()
+	w.wasmProject = wasmProject
+	if err != nil {
+		return Err("generating wasm_exec.js:", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(destPath), 0755); err != nil {
+		return Err("creating zip destination:", err)
+	}
+
+	f, err := os.Create(destPath)
+	if err != nil {
+		return Err("creating zip:", err)
+	}
+
+	zw := zip.NewWriter(f)
+	entries := []struct {
+		name string
+		data []byte
+	}{
+		{filepath.Base(outputPath), wasm},
+		{"wasm_exec.js", []byte(js)},
+		{"index.html", []byte(w.indexHTML("wasm_exec.js", js))},
+	}
+	for _, e := range entries {
+		entry, err := zw.CreateHeader(&zip.FileHeader{Name: e.name, Method: zip.Deflate})
+		if err == nil {
+			_, err = entry.Write(e.data)
+		}
+		if err != nil {
+			zw.Close()
+			f.Close()
+			os.Remove(destPath)
+			return Err("writing", e.name, "to zip:", err)
+		}
+	}
+
+	if err := zw.Close(); err != nil {
+		f.Close()
+		os.Remove(destPath)
+		return Err("finalizing zip:", err)
+	}
+	return f.Close()
+}
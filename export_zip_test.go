@@ -0,0 +1,79 @@
+package tinywasm
+
+import (
+	"archive/zip"
+	"io"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestExportZip(t *testing.T) {
+	tmp := t.TempDir()
+	writeWasmProject(t, tmp)
+
+	w := New(&Config{
+		AppRootDir:              tmp,
+		SourceDir:               "web",
+		OutputDir:               "web/public",
+		MainInputFile:           "main.go",
+		DisableWasmExecJsOutput: true,
+		Logger:                  func(...any) {},
+	})
+
+	dest := filepath.Join(tmp, "dist", "demo.zip")
+	if err := w.ExportZip(dest); err != nil {
+		t.Fatalf("ExportZip failed: %v", err)
+	}
+
+	zr, err := zip.OpenReader(dest)
+	if err != nil {
+		t.Fatalf("opening zip: %v", err)
+	}
+	defer zr.Close()
+
+	contents := make(map[string]string)
+	for _, f := range zr.File {
+		rc, err := f.Open()
+		if err != nil {
+			t.Fatalf("opening %s: %v", f.Name, err)
+		}
+		data, _ := io.ReadAll(rc)
+		rc.Close()
+		contents[f.Name] = string(data)
+	}
+
+	if wasm := contents["main.wasm"]; !strings.HasPrefix(wasm, "\x00asm") {
+		t.Error("zip should contain the compiled main.wasm")
+	}
+	if js := contents["wasm_exec.js"]; !strings.Contains(js, `fetch("main.wasm")`) {
+		t.Error("zip wasm_exec.js should include the initialization code")
+	}
+	if html := contents["index.html"]; !strings.Contains(html, `<script src="wasm_exec.js" defer></script>`) {
+		t.Errorf("zip index.html should load wasm_exec.js, got:\n%s", html)
+	}
+}
+
+// TestExportZipKeepsDetection verifies exporting doesn't mark an undetected instance as a
+// WASM project
+func TestExportZipKeepsDetection(t *testing.T) {
+	tmp := t.TempDir()
+	writeWasmProject(t, tmp)
+
+	w := New(&Config{
+		AppRootDir:              tmp,
+		SourceDir:               "web",
+		OutputDir:               "web/public",
+		MainInputFile:           "main.go",
+		DisableWasmExecJsOutput: true,
+		Logger:                  func(...any) {},
+	})
+	w.wasmProject = false // eg: detection not run yet
+
+	if err := w.ExportZip(filepath.Join(tmp, "demo.zip")); err != nil {
+		t.Fatalf("ExportZip failed: %v", err)
+	}
+	if w.wasmProject {
+		t.Error("ExportZip should not change the WASM project detection")
+	}
+}
@@ -1,11 +1,32 @@
 package tinywasm
 
 import (
+	"path/filepath"
+	"slices"
+	"strings"
+
 	. "github.com/cdvelop/tinystring"
 )
 
+// SupportedExtensions returns ".go" plus the extensions of any go:embed assets
+// of the main package (see EmbeddedAssetPaths), so edits to embedded files
+// also reach NewFileEvent. ".mod" and ".sum" are included when
+// Config.RebuildOnDepChange is set.
 func (w *TinyWasm) SupportedExtensions() []string {
-	return []string{".go"}
+	extensions := []string{".go"}
+	if w.Config.RebuildOnDepChange {
+		extensions = append(extensions, ".mod", ".sum")
+	}
+
+	assets, _ := w.EmbeddedAssetPaths()
+	for _, asset := range assets {
+		ext := filepath.Ext(asset)
+		if ext != "" && !slices.Contains(extensions, ext) {
+			extensions = append(extensions, ext)
+		}
+	}
+
+	return extensions
 }
 
 // NewFileEvent handles file events for WASM compilation with automatic project detection
@@ -22,8 +43,18 @@ func (w *TinyWasm) NewFileEvent(fileName, extension, filePath, event string) err
 
 	w.Logger(extension, event, "...", filePath)
 
-	// Only process Go files for compilation triggers
-	if extension != ".go" {
+	// Only process Go files, module files and go:embed assets for compilation triggers
+	if extension == ".go" {
+		w.embeddedAssets = nil // directives may have changed; re-read on next asset event
+	} else if !w.isDependencyFile(fileName, filePath) && !w.isEmbeddedAsset(filePath, event) {
+		return nil
+	}
+
+	// The main input moved away or was deleted: stop building a path that no longer exists
+	if event == "remove" || event == "rename" {
+		if w.isMainInputFile(filePath) {
+			w.mainInputRemoved()
+		}
 		return nil
 	}
 
@@ -32,29 +63,112 @@ func (w *TinyWasm) NewFileEvent(fileName, extension, filePath, event string) err
 		return nil
 	}
 
+	// Files excluded by .tinywasmignore never trigger compilation
+	if w.IsIgnored(filePath) {
+		return nil
+	}
+
+	// Second half of a rename: the main input reappeared in another directory
+	if event == "create" && fileName == w.Config.MainInputFile && !w.isMainInputFile(filePath) {
+		w.relocateMainInput(filePath)
+	}
+
+	// Cold start: New ran detection before the main file existed, detect again now
+	if event == "create" && !w.wasmProject && (fileName == w.Config.MainInputFile || HasSuffix(fileName, ".wasm.go")) {
+		w.detectProjectConfiguration()
+	}
+
 	// IMPORTANT: At this point, devwatch has already called godepfind.ThisFileIsMine()
 	// and confirmed this file belongs to this handler. We should ALWAYS compile.
 	// The old ShouldCompileToWasm() check was incorrect - it rejected dependency files.
 
 	// Compile using current active builder
-	if w.activeBuilder == nil {
+	if w.currentBuilder() == nil {
 		return Err("builder not initialized")
 	}
 
-	w.Logger("Compiling WASM due to", filePath, "change...")
+	// Bursts of events compile once (see Config.DebounceInterval)
+	if w.Config.DebounceInterval > 0 {
+		w.debounceCompile(filePath)
+		return nil
+	}
+
+	return w.compileForChanges(w.Value(), filePath)
+}
+
+// compileForChanges compiles what changes to filePaths affect with mode: the
+// main program once when any file outside the extra entry inputs changed since the last
+// build, plus every entry when one of them is not the main input (entries may import
+// it); otherwise only the entry points whose input changed.
+func (w *TinyWasm) compileForChanges(mode string, filePaths ...string) error {
+	var changed, entryInputs []string
+	rebuildEntries := false
+	for _, filePath := range filePaths {
+		// The input of an extra entry point only affects that program
+		if w.entryForFile(filePath) != nil {
+			entryInputs = append(entryInputs, filePath)
+			continue
+		}
+		// Duplicate events for an unchanged file (see Config.ForceRecompile)
+		if w.unchangedSinceLastBuild(mode, filePath) {
+			w.Logger("Skipping compilation,", filePath, "unchanged since the last build")
+			continue
+		}
+		changed = append(changed, filePath)
+		rebuildEntries = rebuildEntries || !w.isMainInputFile(filePath)
+	}
+
+	if len(changed) > 0 {
+		w.Logger("Compiling WASM due to", strings.Join(changed, ", "), "change...")
+
+		// Compile using gobuild, coalescing bursts of events (see Config.MaxPendingBuilds)
+		if err := w.queueBuild(mode); err != nil {
+			return Err("compiling to WebAssembly error:", err.Error())
+		}
+	}
 
-	// Compile using gobuild
-	if err := w.activeBuilder.CompileProgram(); err != nil {
-		return Err("compiling to WebAssembly error: ", err)
+	if rebuildEntries {
+		if err := w.compileEntries(mode); err != nil {
+			return Err("compiling to WebAssembly error:", err.Error())
+		}
+	} else {
+		for _, filePath := range entryInputs {
+			eb := w.entryForFile(filePath)
+			w.Logger("Compiling entry point", eb.entry.OutputName, "due to", filePath, "change...")
+			if err := w.compileWith(eb.builders[mode], mode); err != nil {
+				return Err("compiling to WebAssembly error:", err.Error())
+			}
+		}
 	}
 
-	w.Logger("✓ WASM compilation successful")
+	if len(changed) > 0 {
+		w.Logger("✓ WASM compilation successful")
+	}
 
 	return nil
 }
 
+// isDependencyFile reports whether filePath is the go.mod or go.sum of AppRootDir, whose
+// changes rebuild when Config.RebuildOnDepChange is set
+func (w *TinyWasm) isDependencyFile(fileName, filePath string) bool {
+	if !w.Config.RebuildOnDepChange || (fileName != "go.mod" && fileName != "go.sum") {
+		return false
+	}
+	dir, err := filepath.Abs(filepath.Dir(filePath))
+	if err != nil {
+		return false
+	}
+	root, err := filepath.Abs(w.Config.AppRootDir)
+	return err == nil && dir == root
+}
+
 // ShouldCompileToWasm determines if a file should trigger WASM compilation
 func (w *TinyWasm) ShouldCompileToWasm(fileName, filePath string) bool {
+	// Excluded by .tinywasmignore
+	if filePath != "" && w.IsIgnored(filePath) {
+		return false
+	}
+
 	// Always compile main.wasm.go
 	if fileName == w.Config.MainInputFile {
 		return true
@@ -79,11 +193,50 @@ func (w *TinyWasm) MainInputFileRelativePath() string {
 // MainOutputFileAbsolutePath returns the absolute path to the main WASM output file (e.g. "main.wasm").
 func (w *TinyWasm) MainOutputFileAbsolutePath() string {
 	// The output file is created in OutputDir which is:
-	// AppRootDir/OutputDir/main.wasm
-	return PathJoin(w.Config.AppRootDir, w.Config.OutputDir, "main.wasm").String()
+	// AppRootDir/OutputDir/main.wasm (main.debug.wasm for Medium with DebugOutputSuffix ".debug")
+	return PathJoin(w.Config.AppRootDir, w.Config.OutputDir, w.outName(w.Value())+".wasm").String()
 }
 
 // UnobservedFiles returns files that should not be watched for changes e.g: main.wasm
 func (w *TinyWasm) UnobservedFiles() []string {
-	return w.activeBuilder.UnobservedFiles()
+	files := w.currentBuilder().UnobservedFiles()
+
+	outputs := []string{w.currentBuilder().MainOutputFileNameWithExtension()}
+	if w.Config.HashedOutputName {
+		// eg: main.????????.wasm
+		outputs = append(outputs, hashedOutputGlob(outputs[0]))
+		files = append(files, outputs[1])
+	}
+	for _, output := range outputs {
+		if w.Config.EmitGzip {
+			files = append(files, output+gzipExtension)
+		}
+		if w.Config.EmitBrotli {
+			files = append(files, output+brotliExtension)
+		}
+		if w.Config.EmitSymbols {
+			files = append(files, output+symbolsFileExtension)
+		}
+	}
+
+	if w.Config.EmitBuildStats {
+		files = append(files, buildStatsFileName)
+	}
+
+	// Outputs of Config.ExtraEntryPoints
+	for _, eb := range w.entries {
+		b := eb.builders[w.Value()]
+		files = append(files, b.UnobservedFiles()...)
+		output := b.MainOutputFileNameWithExtension()
+		if w.Config.EmitGzip {
+			files = append(files, output+gzipExtension)
+		}
+		if w.Config.EmitBrotli {
+			files = append(files, output+brotliExtension)
+		}
+		if w.Config.EmitSymbols {
+			files = append(files, output+symbolsFileExtension)
+		}
+	}
+	return files
 }
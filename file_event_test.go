@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 	"testing"
 )
@@ -208,3 +209,178 @@ func TestUnobservedFiles(t *testing.T) {
 		}
 	}
 }
+
+// TestNewFileEventDetectsProjectOnMainFileCreate verifies detection re-runs when the main
+// file appears after New, so the first build also produces wasm_exec.js
+func TestNewFileEventDetectsProjectOnMainFileCreate(t *testing.T) {
+	tmp := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(tmp, "web", "public"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(tmp, "go.mod"), []byte("module test\n\ngo 1.21\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	w := New(&Config{
+		AppRootDir:          tmp,
+		SourceDir:           "web",
+		OutputDir:           "web/public",
+		WasmExecJsOutputDir: "web/js",
+		MainInputFile:       "main.wasm.go",
+		Logger:              func(...any) {},
+	})
+	if w.wasmProject {
+		t.Fatal("no WASM project should be detected before the main file exists")
+	}
+
+	mainPath := filepath.Join(tmp, "web", "main.wasm.go")
+	if err := os.WriteFile(mainPath, []byte("package main\n\nfunc main() {}\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := w.NewFileEvent("main.wasm.go", ".go", mainPath, "create"); err != nil {
+		t.Fatalf("NewFileEvent failed: %v", err)
+	}
+
+	if !w.wasmProject {
+		t.Error("expected WASM project to be detected on main file creation")
+	}
+	if _, err := os.Stat(filepath.Join(tmp, "web", "js", "wasm_exec.js")); err != nil {
+		t.Errorf("expected wasm_exec.js to be generated: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(tmp, "web", "public", "main.wasm")); err != nil {
+		t.Errorf("expected main.wasm to be compiled: %v", err)
+	}
+}
+
+// TestNewFileEventRebuildsOnDependencyChange verifies go.mod/go.sum changes trigger a rebuild
+func TestNewFileEventRebuildsOnDependencyChange(t *testing.T) {
+	tmp := t.TempDir()
+	writeWasmProject(t, tmp)
+
+	// Enabled by default, also without NewConfig
+	w := New(&Config{
+		AppRootDir:              tmp,
+		SourceDir:               "web",
+		OutputDir:               "web/public",
+		DisableWasmExecJsOutput: true,
+		Logger:                  func(...any) {},
+	})
+
+	for _, ext := range []string{".mod", ".sum"} {
+		if !slices.Contains(w.SupportedExtensions(), ext) {
+			t.Errorf("SupportedExtensions() missing %s", ext)
+		}
+	}
+
+	output := filepath.Join(tmp, "web", "public", "main.wasm")
+	goMod := filepath.Join(tmp, "go.mod")
+	if err := w.NewFileEvent("go.mod", ".mod", goMod, "write"); err != nil {
+		t.Fatalf("NewFileEvent failed: %v", err)
+	}
+	if _, err := os.Stat(output); err != nil {
+		t.Fatalf("expected go.mod change to rebuild main.wasm: %v", err)
+	}
+
+	// Disabled: dependency files are ignored
+	os.Remove(output)
+	w.Config.RebuildOnDepChange = false
+	if err := w.NewFileEvent("go.mod", ".mod", goMod, "write"); err != nil {
+		t.Fatalf("NewFileEvent failed: %v", err)
+	}
+	if _, err := os.Stat(output); err == nil {
+		t.Error("go.mod change should not rebuild without RebuildOnDepChange")
+	}
+
+	// Disabled from NewConfig: New keeps the explicit false
+	c := NewConfig()
+	if !c.RebuildOnDepChange {
+		t.Error("NewConfig should enable RebuildOnDepChange")
+	}
+	c.AppRootDir = tmp
+	c.RebuildOnDepChange = false
+	if New(c).Config.RebuildOnDepChange {
+		t.Error("New should keep RebuildOnDepChange disabled on a NewConfig config")
+	}
+}
+
+// TestNewFileEventFollowsMovedMainInput verifies removing the main input resets detection
+// and recreating it in another directory rebuilds from there
+func TestNewFileEventFollowsMovedMainInput(t *testing.T) {
+	tmp := t.TempDir()
+	writeWasmProject(t, tmp)
+
+	w := New(&Config{
+		AppRootDir:              tmp,
+		SourceDir:               "web",
+		OutputDir:               "web/public",
+		MainInputFile:           "main.go",
+		DisableWasmExecJsOutput: true,
+		Logger:                  func(...any) {},
+	})
+	if !w.DetectionReport().IsWasmProject {
+		t.Fatal("project should be detected from web/main.go")
+	}
+
+	oldPath := filepath.Join(tmp, "web", "main.go")
+	newPath := filepath.Join(tmp, "client", "main.go")
+	if err := os.MkdirAll(filepath.Dir(newPath), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Rename(oldPath, newPath); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := w.NewFileEvent("main.go", ".go", oldPath, "rename"); err != nil {
+		t.Fatalf("rename event failed: %v", err)
+	}
+	if w.wasmProject || w.DetectionReport().IsWasmProject {
+		t.Error("detection state should be cleared once the main input is gone")
+	}
+
+	if err := w.NewFileEvent("main.go", ".go", newPath, "create"); err != nil {
+		t.Fatalf("create event failed: %v", err)
+	}
+	if w.Config.SourceDir != "client" {
+		t.Errorf("SourceDir = %q, want client", w.Config.SourceDir)
+	}
+	if !w.wasmProject {
+		t.Error("project should be detected again from the new location")
+	}
+	if _, err := os.Stat(filepath.Join(tmp, "web", "public", "main.wasm")); err != nil {
+		t.Errorf("moved main input should be compiled: %v", err)
+	}
+}
+
+// TestNewFileEventReportsCompilerDiagnostics verifies a failed build returns the compiler
+// output with absolute file:line:col positions, also through OnBuildError
+func TestNewFileEventReportsCompilerDiagnostics(t *testing.T) {
+	tmp := t.TempDir()
+	writeWasmProject(t, tmp)
+	mainPath := filepath.Join(tmp, "web", "main.go")
+	if err := os.WriteFile(mainPath, []byte("package main\n\nfunc main() {\n\tx := 1\n}\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var hookErr error
+	w := New(&Config{
+		AppRootDir:              tmp,
+		SourceDir:               "web",
+		OutputDir:               "web/public",
+		DisableWasmExecJsOutput: true,
+		OnBuildError:            func(mode string, err error) { hookErr = err },
+		Logger:                  func(...any) {},
+	})
+
+	err := w.NewFileEvent("main.go", ".go", mainPath, "write")
+	if err == nil {
+		t.Fatal("expected a compile error")
+	}
+	diagnostic := mainPath + ":4:2: declared and not used"
+	if !strings.Contains(err.Error(), "\n"+diagnostic) || !strings.HasPrefix(err.Error(), "compiling to WebAssembly error:") {
+		t.Errorf("error = %q, want the diagnostic %q on its own line", err, diagnostic)
+	}
+	if hookErr == nil || !strings.Contains(hookErr.Error(), diagnostic) {
+		t.Errorf("OnBuildError got %v, want the compiler diagnostics", hookErr)
+	}
+}
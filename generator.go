@@ -6,6 +6,7 @@ import (
 	"path/filepath"
 
 	"github.com/cdvelop/mdgo"
+	. "github.com/cdvelop/tinystring"
 )
 
 //go:embed templates/*
@@ -14,24 +15,34 @@ var embeddedFS embed.FS
 // CreateDefaultWasmFileClientIfNotExist creates a default WASM main.go file from the embedded markdown template
 // It never overwrites an existing file and returns the TinyWasm instance for method chaining.
 func (t *TinyWasm) CreateDefaultWasmFileClientIfNotExist() *TinyWasm {
+	if err := t.GenerateWasmFile(false); err != nil && t.Logger != nil {
+		t.Logger(err)
+	}
+	return t
+}
+
+// GenerateWasmFile writes the default main file (see DefaultWasmTemplatePath) to
+// SourceDir/MainInputFile and marks the project as wasm. An existing file is kept unless
+// overwrite is set, eg: for a "reset to template" command. Returns the template read and
+// mdgo extraction errors.
+func (t *TinyWasm) GenerateWasmFile(overwrite bool) error {
 	// Build target path from Config
 	targetPath := filepath.Join(t.AppRootDir, t.SourceDir, t.MainInputFile)
 
-	// Never overwrite existing files
-	if _, err := os.Stat(targetPath); err == nil {
+	// Never overwrite existing files unless asked to
+	_, statErr := os.Stat(targetPath)
+	exists := statErr == nil
+	if exists && !overwrite {
 		if t.Logger != nil {
 			t.Logger("WASM file already exists at", targetPath, ", skipping generation")
 		}
-		return t
+		return nil
 	}
 
-	// Read embedded markdown (no template processing needed - static content)
-	raw, errRead := embeddedFS.ReadFile("templates/basic_wasm_client.md")
+	// Read the markdown template (no template processing needed - static content)
+	raw, errRead := t.defaultWasmTemplate()
 	if errRead != nil {
-		if t.Logger != nil {
-			t.Logger("Error reading embedded template:", errRead)
-		}
-		return t
+		return Err("reading main file template:", errRead.Error())
 	}
 
 	// Use mdgo to extract Go code from markdown
@@ -54,14 +65,15 @@ func (t *TinyWasm) CreateDefaultWasmFileClientIfNotExist() *TinyWasm {
 
 	// Extract to the main file
 	if err := m.Extract(t.MainInputFile); err != nil {
-		if t.Logger != nil {
-			t.Logger("Error extracting go code from markdown:", err)
-		}
-		return t
+		return Err("extracting go code from markdown:", err.Error())
 	}
 
 	if t.Logger != nil {
-		t.Logger("Generated WASM file at", targetPath)
+		if exists {
+			t.Logger("Replaced WASM file at", targetPath, "with the template")
+		} else {
+			t.Logger("Generated WASM file at", targetPath)
+		}
 	}
 
 	t.wasmProject = true
@@ -74,5 +86,28 @@ func (t *TinyWasm) CreateDefaultWasmFileClientIfNotExist() *TinyWasm {
 		t.wasmProjectWriteOrReplaceWasmExecJsOutput()
 	}
 
-	return t
+	return nil
+}
+
+// defaultWasmTemplate returns the markdown the default main file is extracted from:
+// Config.DefaultWasmTemplatePath when set (a .go file is wrapped in a go code block),
+// otherwise the embedded templates/basic_wasm_client.md. An unreadable custom template
+// is an error: silently generating the embedded starter would hide a wrong path.
+func (t *TinyWasm) defaultWasmTemplate() ([]byte, error) {
+	templatePath := t.Config.DefaultWasmTemplatePath
+	if templatePath == "" {
+		return embeddedFS.ReadFile("templates/basic_wasm_client.md")
+	}
+
+	if !filepath.IsAbs(templatePath) {
+		templatePath = filepath.Join(t.AppRootDir, templatePath)
+	}
+	raw, err := os.ReadFile(templatePath)
+	if err != nil {
+		return nil, Err("DefaultWasmTemplatePath:", err.Error())
+	}
+	if filepath.Ext(templatePath) == ".go" {
+		raw = []byte("```go\n" + string(raw) + "\n```\n")
+	}
+	return raw, nil
 }
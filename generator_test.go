@@ -104,3 +104,91 @@ func TestCreateDefaultWasmFileClientIfNotExistDoesNotOverwrite(t *testing.T) {
 		t.Fatalf("file was overwritten, expected original content")
 	}
 }
+
+// TestDefaultWasmTemplatePath verifies a custom .go or markdown starter replaces the embedded one
+func TestDefaultWasmTemplatePath(t *testing.T) {
+	for name, template := range map[string]string{
+		"starter.go": "package main\n\n// ACME starter\nfunc main() {}",
+		"starter.md": "# ACME\n\n```go\npackage main\n\n// ACME starter\nfunc main() {}\n```\n",
+	} {
+		t.Run(name, func(t *testing.T) {
+			tmp := t.TempDir()
+			if err := os.WriteFile(filepath.Join(tmp, name), []byte(template), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			w := New(&Config{
+				AppRootDir:              tmp,
+				SourceDir:               "web",
+				OutputDir:               "web/public",
+				DefaultWasmTemplatePath: name,
+				DisableWasmExecJsOutput: true,
+				Logger:                  func(...any) {},
+			})
+			w.CreateDefaultWasmFileClientIfNotExist()
+
+			content, err := os.ReadFile(filepath.Join(tmp, "web", "main.go"))
+			if err != nil {
+				t.Fatalf("main file not generated: %v", err)
+			}
+			if !strings.Contains(string(content), "// ACME starter") {
+				t.Errorf("generated file does not come from the custom template:\n%s", content)
+			}
+			if !w.wasmProject {
+				t.Error("generating the main file should mark the project as wasm")
+			}
+		})
+	}
+}
+
+// TestGenerateWasmFileOverwrite verifies an existing main file is only replaced on request
+func TestGenerateWasmFileOverwrite(t *testing.T) {
+	tmp := t.TempDir()
+	target := filepath.Join(tmp, "web", "main.go")
+	if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(target, []byte("// STUB"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	w := New(&Config{
+		AppRootDir:              tmp,
+		SourceDir:               "web",
+		OutputDir:               "web/public",
+		DisableWasmExecJsOutput: true,
+		Logger:                  func(...any) {},
+	})
+
+	if err := w.GenerateWasmFile(false); err != nil {
+		t.Fatalf("GenerateWasmFile(false) failed: %v", err)
+	}
+	if content, _ := os.ReadFile(target); string(content) != "// STUB" {
+		t.Errorf("existing file changed without overwrite: %s", content)
+	}
+
+	if err := w.GenerateWasmFile(true); err != nil {
+		t.Fatalf("GenerateWasmFile(true) failed: %v", err)
+	}
+	if content, _ := os.ReadFile(target); !strings.Contains(string(content), "func main()") {
+		t.Errorf("file not replaced with the template: %s", content)
+	}
+
+	w.Config.DefaultWasmTemplatePath = "empty.md"
+	if err := os.WriteFile(filepath.Join(tmp, "empty.md"), []byte("# no code"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.GenerateWasmFile(true); err == nil {
+		t.Error("GenerateWasmFile should report a template without go code")
+	}
+
+	// A wrong path is reported instead of falling back to the embedded template
+	os.Remove(target)
+	w.Config.DefaultWasmTemplatePath = "missing.go"
+	if err := w.GenerateWasmFile(false); err == nil || !strings.Contains(err.Error(), "missing.go") {
+		t.Errorf("GenerateWasmFile error = %v, want the read error of missing.go", err)
+	}
+	if _, err := os.Stat(target); err == nil {
+		t.Error("no main file should be generated from an unreadable template")
+	}
+}
@@ -7,3 +7,5 @@ require github.com/cdvelop/gobuild v0.0.16
 require github.com/cdvelop/tinystring v0.10.4
 
 require github.com/cdvelop/mdgo v0.0.3
+
+require github.com/andybalholm/brotli v1.2.5
@@ -0,0 +1,103 @@
+package tinywasm
+
+import (
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/cdvelop/gobuild"
+	. "github.com/cdvelop/tinystring"
+)
+
+// hashLength is the number of hex digits of the content hash in hashed output names
+const hashLength = 8
+
+// HashedOutputName returns the file name of the current hashed wasm output of the
+// active mode, eg: "main.1a2b3c4d.wasm" (see Config.HashedOutputName). The generated
+// JS fetches this name, use it for preload hints or deploy manifests.
+func (w *TinyWasm) HashedOutputName() (string, error) {
+	if !w.Config.HashedOutputName {
+		return "", Errf("hashed output names disabled, set Config.HashedOutputName")
+	}
+	hashed := latestHashedOutput(w.currentBuilder())
+	if hashed == "" {
+		return "", Errf("no hashed wasm output built yet for mode %s", w.Value())
+	}
+	return filepath.Base(hashed), nil
+}
+
+// outputPathFor returns the wasm output of b as it is on disk: the latest hashed
+// variant when Config.HashedOutputName is set and one exists, the builder's final
+// output path otherwise
+func (w *TinyWasm) outputPathFor(b *gobuild.GoBuild) string {
+	if w.Config.HashedOutputName {
+		if hashed := latestHashedOutput(b); hashed != "" {
+			return hashed
+		}
+	}
+	return b.FinalOutputPath()
+}
+
+// wasmFileName returns the wasm file name fetched by the generated JS for the active mode
+func (w *TinyWasm) wasmFileName() string {
+	return filepath.Base(w.outputPathFor(w.currentBuilder()))
+}
+
+// hashedOutputGlob returns the pattern matching every hashed variant of outputPath,
+// eg: "public/main.????????.wasm" for "public/main.wasm"
+func hashedOutputGlob(outputPath string) string {
+	return strings.TrimSuffix(outputPath, ".wasm") + "." + strings.Repeat("?", hashLength) + ".wasm"
+}
+
+// hashedOutputs returns the existing hashed variants of outputPath
+func hashedOutputs(outputPath string) []string {
+	matches, _ := filepath.Glob(hashedOutputGlob(outputPath))
+
+	hashed := matches[:0]
+	for _, m := range matches {
+		hash := strings.TrimSuffix(strings.TrimPrefix(m, strings.TrimSuffix(outputPath, ".wasm")+"."), ".wasm")
+		if _, err := hex.DecodeString(hash); err == nil {
+			hashed = append(hashed, m)
+		}
+	}
+	return hashed
+}
+
+// latestHashedOutput returns the most recently written hashed variant of b's output, "" if none
+func latestHashedOutput(b *gobuild.GoBuild) string {
+	var latest string
+	var latestTime int64
+	for _, m := range hashedOutputs(b.FinalOutputPath()) {
+		if info, err := os.Stat(m); err == nil && (latest == "" || info.ModTime().UnixNano() > latestTime) {
+			latest, latestTime = m, info.ModTime().UnixNano()
+		}
+	}
+	return latest
+}
+
+// applyHashedOutputName renames the fresh build at outputPath to its content hashed
+// name and removes the previous hashed variants with their companion files,
+// returning the new path
+func applyHashedOutputName(outputPath string) (string, error) {
+	hash, err := fileSHA256(outputPath)
+	if err != nil {
+		return "", Err("hashing wasm output:", err)
+	}
+	hashed := strings.TrimSuffix(outputPath, ".wasm") + "." + hash[:hashLength] + ".wasm"
+
+	for _, previous := range hashedOutputs(outputPath) {
+		if previous == hashed {
+			continue
+		}
+		for _, ext := range []string{"", gzipExtension, brotliExtension, symbolsFileExtension} {
+			os.Remove(previous + ext)
+		}
+	}
+	removeCompressedCopies(outputPath)
+
+	if err := os.Rename(outputPath, hashed); err != nil {
+		return "", Err("renaming wasm output to its hashed name:", err)
+	}
+	return hashed, nil
+}
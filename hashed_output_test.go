@@ -0,0 +1,75 @@
+package tinywasm
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestHashedOutputName(t *testing.T) {
+	tmp := t.TempDir()
+	w := New(&Config{
+		AppRootDir:              tmp,
+		OutputDir:               "public",
+		HashedOutputName:        true,
+		EmitGzip:                true,
+		DisableWasmExecJsOutput: true,
+		Logger:                  func(...any) {},
+	})
+
+	if files := w.UnobservedFiles(); !slices.Contains(files, "main.????????.wasm") || !slices.Contains(files, "main.????????.wasm.gz") {
+		t.Errorf("UnobservedFiles() = %v, missing hashed output globs", files)
+	}
+	if _, err := w.HashedOutputName(); err == nil {
+		t.Error("expected error before any build")
+	}
+
+	output := filepath.Join(tmp, "public", "main.wasm")
+	if err := os.MkdirAll(filepath.Dir(output), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	var previous string
+	for _, content := range []string{"\x00asm first build", "\x00asm second build"} {
+		if err := os.WriteFile(output, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if err := w.afterCompile(w.builderLarge, w.Config.BuildLargeSizeShortcut, nil); err != nil {
+			t.Fatalf("afterCompile failed: %v", err)
+		}
+
+		name, err := w.HashedOutputName()
+		if err != nil {
+			t.Fatalf("HashedOutputName failed: %v", err)
+		}
+		hash, _ := fileSHA256(filepath.Join(tmp, "public", name))
+		if name != "main."+hash[:8]+".wasm" {
+			t.Errorf("HashedOutputName() = %q, want main.%s.wasm", name, hash[:8])
+		}
+
+		if _, err := os.Stat(output); !os.IsNotExist(err) {
+			t.Error("unhashed output should have been renamed")
+		}
+		if _, err := os.Stat(filepath.Join(tmp, "public", name+gzipExtension)); err != nil {
+			t.Errorf("gzip copy of hashed output not written: %v", err)
+		}
+		if previous != "" {
+			if _, err := os.Stat(filepath.Join(tmp, "public", previous)); !os.IsNotExist(err) {
+				t.Errorf("previous hashed output %s should have been removed", previous)
+			}
+			if _, err := os.Stat(filepath.Join(tmp, "public", previous+gzipExtension)); !os.IsNotExist(err) {
+				t.Errorf("gzip copy of previous hashed output %s should have been removed", previous)
+			}
+		}
+
+		if footer := w.defaultJsFooter(); !strings.Contains(footer, `fetch("`+name+`")`) {
+			t.Errorf("footer should fetch %s:\n%s", name, footer)
+		}
+		if size, err := w.GetWasmSize(); err != nil || size != int64(len(content)) {
+			t.Errorf("GetWasmSize() = %d, %v; want %d", size, err, len(content))
+		}
+		previous = name
+	}
+}
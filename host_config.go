@@ -0,0 +1,88 @@
+package tinywasm
+
+import (
+	"path/filepath"
+	"strings"
+
+	. "github.com/cdvelop/tinystring"
+)
+
+// Supported targets of GenerateHostConfig
+const (
+	HostNginx = "nginx"
+	HostCaddy = "caddy"
+)
+
+// GenerateHostConfig returns a snippet to paste inside a server block ("nginx") or
+// site block ("caddy") serving OutputDir with the headers a wasm app needs: the
+// application/wasm MIME type, the precompressed .br/.gz variants when
+// ServePrecompressed is set and the COOP/COEP headers when CrossOriginIsolated is set.
+func (w *TinyWasm) GenerateHostConfig(target string) (string, error) {
+	root, err := filepath.Abs(filepath.Join(w.Config.AppRootDir, w.Config.OutputDir))
+	if err != nil {
+		return "", Err("resolving output directory:", err)
+	}
+	root = filepath.ToSlash(root)
+
+	switch target {
+	case HostNginx:
+		return w.nginxHostConfig(root), nil
+	case HostCaddy:
+		return w.caddyHostConfig(root), nil
+	}
+	return "", Errf("unsupported host config target %q, use %q or %q", target, HostNginx, HostCaddy)
+}
+
+// nginxHostConfig builds the nginx snippet. The MIME type is set inside the wasm
+// location with an empty types block, because a server-level types block would
+// replace nginx's whole default MIME map.
+func (w *TinyWasm) nginxHostConfig(root string) string {
+	var b strings.Builder
+	b.WriteString("# TinyWasm: " + w.Config.OutputName + ".wasm\n")
+	b.WriteString("root " + root + ";\n")
+
+	if w.Config.CrossOriginIsolated {
+		b.WriteString("add_header Cross-Origin-Opener-Policy \"same-origin\" always;\n")
+		b.WriteString("add_header Cross-Origin-Embedder-Policy \"require-corp\" always;\n")
+	}
+
+	b.WriteString("\nlocation ~ \\.wasm$ {\n")
+	b.WriteString("    types { }\n")
+	b.WriteString("    default_type application/wasm;\n")
+	if w.Config.ServePrecompressed {
+		b.WriteString("    gzip_static on;\n")
+		b.WriteString("    brotli_static on; # requires ngx_brotli\n")
+	}
+	if w.Config.CrossOriginIsolated {
+		// add_header in a location drops the ones inherited from the server block
+		b.WriteString("    add_header Cross-Origin-Opener-Policy \"same-origin\" always;\n")
+		b.WriteString("    add_header Cross-Origin-Embedder-Policy \"require-corp\" always;\n")
+	}
+	b.WriteString("}\n")
+
+	return b.String()
+}
+
+// caddyHostConfig builds the Caddyfile snippet
+func (w *TinyWasm) caddyHostConfig(root string) string {
+	var b strings.Builder
+	b.WriteString("# TinyWasm: " + w.Config.OutputName + ".wasm\n")
+	b.WriteString("root * " + root + "\n")
+	b.WriteString("\n@wasm path *.wasm\n")
+	b.WriteString("header @wasm Content-Type application/wasm\n")
+
+	if w.Config.CrossOriginIsolated {
+		b.WriteString("header Cross-Origin-Opener-Policy same-origin\n")
+		b.WriteString("header Cross-Origin-Embedder-Policy require-corp\n")
+	}
+
+	if w.Config.ServePrecompressed {
+		b.WriteString("\nfile_server {\n")
+		b.WriteString("    precompressed br gzip\n")
+		b.WriteString("}\n")
+	} else {
+		b.WriteString("\nfile_server\n")
+	}
+
+	return b.String()
+}
@@ -0,0 +1,70 @@
+package tinywasm
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestGenerateHostConfig(t *testing.T) {
+	tmp := t.TempDir()
+
+	w := New(&Config{
+		AppRootDir:              tmp,
+		SourceDir:               "web",
+		OutputDir:               "web/public",
+		OutputName:              "main",
+		DisableWasmExecJsOutput: true,
+		Logger:                  func(...any) {},
+	})
+
+	nginx, err := w.GenerateHostConfig(HostNginx)
+	if err != nil {
+		t.Fatalf("GenerateHostConfig(nginx) failed: %v", err)
+	}
+	if !strings.Contains(nginx, "default_type application/wasm;") {
+		t.Errorf("nginx snippet missing wasm MIME type:\n%s", nginx)
+	}
+	for _, unexpected := range []string{"Cross-Origin-Opener-Policy", "gzip_static"} {
+		if strings.Contains(nginx, unexpected) {
+			t.Errorf("nginx snippet should not contain %q by default:\n%s", unexpected, nginx)
+		}
+	}
+
+	w.Config.CrossOriginIsolated = true
+	w.Config.ServePrecompressed = true
+
+	nginx, err = w.GenerateHostConfig(HostNginx)
+	if err != nil {
+		t.Fatalf("GenerateHostConfig(nginx) failed: %v", err)
+	}
+	for _, expected := range []string{
+		`add_header Cross-Origin-Opener-Policy "same-origin" always;`,
+		`add_header Cross-Origin-Embedder-Policy "require-corp" always;`,
+		"gzip_static on;",
+		"brotli_static on;",
+	} {
+		if !strings.Contains(nginx, expected) {
+			t.Errorf("nginx snippet missing %q:\n%s", expected, nginx)
+		}
+	}
+
+	caddy, err := w.GenerateHostConfig(HostCaddy)
+	if err != nil {
+		t.Fatalf("GenerateHostConfig(caddy) failed: %v", err)
+	}
+	for _, expected := range []string{
+		"header @wasm Content-Type application/wasm",
+		"header Cross-Origin-Opener-Policy same-origin",
+		"header Cross-Origin-Embedder-Policy require-corp",
+		"precompressed br gzip",
+		"web/public",
+	} {
+		if !strings.Contains(caddy, expected) {
+			t.Errorf("caddy snippet missing %q:\n%s", expected, caddy)
+		}
+	}
+
+	if _, err := w.GenerateHostConfig("apache"); err == nil {
+		t.Error("expected error for unsupported target")
+	}
+}
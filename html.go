@@ -0,0 +1,202 @@
+package tinywasm
+
+import (
+	"crypto/sha512"
+	"encoding/base64"
+	"html"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	. "github.com/cdvelop/tinystring"
+)
+
+// indexHTML returns a minimal page that runs the app: it preloads the wasm and loads
+// the generated wasm_exec.js (runtime plus initialization footer, passed as wasmExecJs)
+// from scriptSrc once the document is parsed. With Config.EmitSRI the script tag carries
+// the integrity hash of wasmExecJs, so the page always matches the JS it is shipped with.
+func (w *TinyWasm) indexHTML(scriptSrc, wasmExecJs string) string {
+	integrity := ""
+	if w.Config.EmitSRI {
+		integrity = ` integrity="` + sriHash([]byte(wasmExecJs)) + `" crossorigin="anonymous"`
+	}
+
+	return `<!DOCTYPE html>
+<html lang="en">
+<head>
+	<meta charset="utf-8">
+	<meta name="viewport" content="width=device-width, initial-scale=1">
+	<title>` + html.EscapeString(w.Config.OutputName) + `</title>
+	` + w.PreloadLinkTag() + `
+	<script src="` + html.EscapeString(scriptSrc) + `"` + integrity + ` defer></script>
+</head>
+<body>
+</body>
+</html>
+`
+}
+
+// loaderScriptPattern matches a script tag already loading wasm_exec.js
+var loaderScriptPattern = regexp.MustCompile(`(?i)<script[^>]*\ssrc\s*=\s*["']?[^"'>\s]*wasm_exec\.js`)
+
+// InjectLoaderIntoHTML adds the wasm_exec.js script tag (runtime plus initialization
+// code) to the existing page set in Config.InjectIntoHTML: before </head>, else before
+// </body>, else at the end. The rest of the page is left untouched and nothing is done
+// when a script loading wasm_exec.js is already present, so it can run on every build.
+// It is also called whenever wasm_exec.js is (re)generated.
+func (w *TinyWasm) InjectLoaderIntoHTML() error {
+	if w.Config.InjectIntoHTML == "" {
+		return nil
+	}
+	if w.Config.DisableWasmExecJsOutput || w.isReactor() {
+		return Errf("no wasm_exec.js is written with this configuration, nothing to inject into %s", w.Config.InjectIntoHTML)
+	}
+
+	htmlPath := w.Config.InjectIntoHTML
+	if !filepath.IsAbs(htmlPath) {
+		htmlPath = filepath.Join(w.Config.AppRootDir, htmlPath)
+	}
+
+	info, err := os.Stat(htmlPath)
+	if err != nil {
+		return Err("reading HTML to inject into:", err)
+	}
+	data, err := os.ReadFile(htmlPath)
+	if err != nil {
+		return Err("reading HTML to inject into:", err)
+	}
+	page := string(data)
+
+	if loaderScriptPattern.MatchString(page) {
+		return nil
+	}
+
+	scriptSrc := "wasm_exec.js"
+	if rel, err := filepath.Rel(filepath.Dir(htmlPath), w.WasmExecJsOutputPath()); err == nil {
+		scriptSrc = filepath.ToSlash(rel)
+	}
+	tag := `<script src="` + html.EscapeString(scriptSrc) + `" defer></script>`
+
+	lower := strings.ToLower(page)
+	switch {
+	case strings.Contains(lower, "</head>"):
+		i := strings.Index(lower, "</head>")
+		page = page[:i] + "\t" + tag + "\n" + page[i:]
+	case strings.Contains(lower, "</body>"):
+		i := strings.LastIndex(lower, "</body>")
+		page = page[:i] + tag + "\n" + page[i:]
+	default:
+		if page != "" && !HasSuffix(page, "\n") {
+			page += "\n"
+		}
+		page += tag + "\n"
+	}
+
+	if err := os.WriteFile(htmlPath, []byte(page), info.Mode().Perm()); err != nil {
+		return Err("writing HTML with loader script:", err)
+	}
+	w.Logger("Injected wasm_exec.js script tag into", htmlPath)
+	return nil
+}
+
+// loaderScriptTagPattern matches a whole script tag loading wasm_exec.js
+var loaderScriptTagPattern = regexp.MustCompile(`(?i)<script[^>]*\ssrc\s*=\s*["']?[^"'>\s]*wasm_exec\.js[^>]*>`)
+
+// integrityAttrPattern matches the integrity attribute of a tag
+var integrityAttrPattern = regexp.MustCompile(`(?i)\sintegrity\s*=\s*("[^"]*"|'[^']*'|[^\s>]+)`)
+
+// refreshLoaderIntegrity updates the integrity hash (Config.EmitSRI) of the wasm_exec.js
+// script tag to wasmExecJs in the pages loading it: the index.html written by InitProject
+// in OutputDir and Config.InjectIntoHTML. Otherwise the browser rejects wasm_exec.js once
+// it is regenerated, eg: on a mode switch. Tags without the attribute are left untouched.
+func (w *TinyWasm) refreshLoaderIntegrity(wasmExecJs string) {
+	if !w.Config.EmitSRI {
+		return
+	}
+
+	pages := []string{filepath.Join(w.Config.AppRootDir, w.Config.OutputDir, "index.html")}
+	if page := w.Config.InjectIntoHTML; page != "" {
+		if !filepath.IsAbs(page) {
+			page = filepath.Join(w.Config.AppRootDir, page)
+		}
+		pages = append(pages, page)
+	}
+
+	integrity := ` integrity="` + sriHash([]byte(wasmExecJs)) + `"`
+	for _, page := range pages {
+		info, err := os.Stat(page)
+		if err != nil {
+			continue
+		}
+		data, err := os.ReadFile(page)
+		if err != nil {
+			w.Logger("Failed to read", page, "to update the wasm_exec.js integrity:", err)
+			continue
+		}
+		updated := loaderScriptTagPattern.ReplaceAllStringFunc(string(data), func(tag string) string {
+			return integrityAttrPattern.ReplaceAllLiteralString(tag, integrity)
+		})
+		if updated == string(data) {
+			continue
+		}
+		if err := os.WriteFile(page, []byte(updated), info.Mode().Perm()); err != nil {
+			w.Logger("Failed to update the wasm_exec.js integrity in", page+":", err)
+		}
+	}
+}
+
+// PreloadLinkTag returns a <link rel="preload"> tag for the wasm output, to place in the
+// page <head> so the download starts before wasm_exec.js runs. The href is the same URL
+// the generated initialization code fetches, and crossorigin is required for the preload
+// to be reused by fetch().
+func (w *TinyWasm) PreloadLinkTag() string {
+	href := html.EscapeString(w.wasmFileName())
+	return `<link rel="preload" href="` + href + `" as="fetch" type="application/wasm" crossorigin>`
+}
+
+// InlineBootstrapHTML returns the wasm_exec.js of the current mode (runtime plus
+// initialization code) wrapped in an inline <script>, for hosts that can't serve a
+// separate JS file, eg: Cloudflare Pages Advanced Mode together with
+// Config.DisableWasmExecJsOutput. Nothing is written to disk.
+func (w *TinyWasm) InlineBootstrapHTML() (string, error) {
+	js, err := w.JavascriptForInitializing()
+	if err != nil {
+		return "", err
+	}
+	if js == "" {
+		return "", Errf("not a wasm project: no wasm_exec.js is generated")
+	}
+	// A literal "</script" in the code would end the element early
+	js = strings.ReplaceAll(js, "</script", `<\/script`)
+	return "<script>\n" + js + "\n</script>\n", nil
+}
+
+// WasmExecJsSRI returns the Subresource Integrity value ("sha384-...") of the
+// wasm_exec.js generated for the current mode, for pages written by hand
+func (w *TinyWasm) WasmExecJsSRI() (string, error) {
+	js, err := w.JavascriptForInitializing()
+	if err != nil {
+		return "", err
+	}
+	if js == "" {
+		return "", Errf("not a wasm project: no wasm_exec.js is generated")
+	}
+	return sriHash([]byte(js)), nil
+}
+
+// WasmSRI returns the Subresource Integrity value ("sha384-...") of the current wasm
+// output, eg: for a <link rel="preload" as="fetch" integrity="..."> hint
+func (w *TinyWasm) WasmSRI() (string, error) {
+	data, err := os.ReadFile(w.outputPathFor(w.currentBuilder()))
+	if err != nil {
+		return "", Err("wasm output not available, build first:", err)
+	}
+	return sriHash(data), nil
+}
+
+// sriHash returns the sha384 Subresource Integrity value of data
+func sriHash(data []byte) string {
+	sum := sha512.Sum384(data)
+	return "sha384-" + base64.StdEncoding.EncodeToString(sum[:])
+}
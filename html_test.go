@@ -0,0 +1,260 @@
+package tinywasm
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestIndexHTMLSubresourceIntegrity(t *testing.T) {
+	tmp := t.TempDir()
+	w := New(&Config{
+		AppRootDir:              tmp,
+		OutputDir:               "public",
+		DisableWasmExecJsOutput: true,
+		Logger:                  func(...any) {},
+	})
+	w.wasmProject = true
+
+	js, err := w.JavascriptForInitializing()
+	if err != nil {
+		t.Fatalf("JavascriptForInitializing failed: %v", err)
+	}
+
+	if page := w.indexHTML("wasm_exec.js", js); strings.Contains(page, "integrity=") {
+		t.Errorf("integrity should only be emitted with EmitSRI, got:\n%s", page)
+	}
+
+	w.Config.EmitSRI = true
+	sri, err := w.WasmExecJsSRI()
+	if err != nil {
+		t.Fatalf("WasmExecJsSRI failed: %v", err)
+	}
+	if !strings.HasPrefix(sri, "sha384-") {
+		t.Errorf("WasmExecJsSRI() = %q, want a sha384 value", sri)
+	}
+	if page := w.indexHTML("wasm_exec.js", js); !strings.Contains(page, `integrity="`+sri+`"`) {
+		t.Errorf("index.html should carry the wasm_exec.js integrity %s, got:\n%s", sri, page)
+	}
+
+	// Known vector: SRI of an empty resource
+	if got := sriHash(nil); got != "sha384-OLBgp1GsljhM2TJ+sbHjaiH9txEUvgdDTAzHv2P24donTt6/529l+9Ua0vFImLlb" {
+		t.Errorf("sriHash(empty) = %s", got)
+	}
+
+	if _, err := w.WasmSRI(); err == nil {
+		t.Error("expected WasmSRI to fail before the output exists")
+	}
+	if err := os.MkdirAll(filepath.Join(tmp, "public"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(tmp, "public", "main.wasm"), nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := w.WasmSRI(); err != nil || got != sriHash(nil) {
+		t.Errorf("WasmSRI() = %q, %v", got, err)
+	}
+}
+
+func TestModeSwitchRefreshesIntegrity(t *testing.T) {
+	tmp := t.TempDir()
+	w := New(&Config{
+		AppRootDir:          tmp,
+		OutputDir:           "public",
+		WasmExecJsOutputDir: "public",
+		EmitSRI:             true,
+		Logger:              func(...any) {},
+	})
+	w.wasmProject = true
+
+	js, err := w.JavascriptForInitializing()
+	if err != nil {
+		t.Fatalf("JavascriptForInitializing failed: %v", err)
+	}
+	index := filepath.Join(tmp, "public", "index.html")
+	if err := os.MkdirAll(filepath.Dir(index), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(index, []byte(w.indexHTML("wasm_exec.js", js)), 0644); err != nil {
+		t.Fatal(err)
+	}
+	before := sriHash([]byte(js))
+
+	w.updateCurrentBuilder(w.Config.BuildMediumSizeShortcut)
+	if err := w.writeWasmExecJs(); err != nil {
+		t.Fatalf("writeWasmExecJs failed: %v", err)
+	}
+
+	written, err := os.ReadFile(filepath.Join(tmp, "public", "wasm_exec.js"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	after := sriHash(written)
+	if after == before {
+		t.Fatal("wasm_exec.js should change with the mode")
+	}
+	page, err := os.ReadFile(index)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(page), `integrity="`+after+`"`) || strings.Contains(string(page), before) {
+		t.Errorf("index.html should carry the integrity of the regenerated wasm_exec.js %s, got:\n%s", after, page)
+	}
+}
+
+func TestHeaderOnlySwitchRefreshesIntegrity(t *testing.T) {
+	tmp := t.TempDir()
+	w := New(&Config{
+		AppRootDir:          tmp,
+		OutputDir:           "public",
+		WasmExecJsOutputDir: "public",
+		EmitSRI:             true,
+		Logger:              func(...any) {},
+	})
+	w.wasmProject = true
+	w.updateCurrentBuilder(w.Config.BuildMediumSizeShortcut)
+
+	if err := w.writeWasmExecJs(); err != nil {
+		t.Fatalf("writeWasmExecJs failed: %v", err)
+	}
+	jsPath := filepath.Join(tmp, "public", "wasm_exec.js")
+	js, err := os.ReadFile(jsPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	index := filepath.Join(tmp, "public", "index.html")
+	if err := os.WriteFile(index, []byte(w.indexHTML("wasm_exec.js", string(js))), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// Medium -> Small shares the runtime: only the header line is rewritten
+	if !w.sameWasmExecJsRuntime(w.Config.BuildMediumSizeShortcut, w.Config.BuildSmallSizeShortcut) {
+		t.Fatal("expected Medium and Small to share the TinyGo runtime")
+	}
+	if !w.updateWasmExecJsHeader(w.Config.BuildSmallSizeShortcut) {
+		t.Fatal("updateWasmExecJsHeader failed")
+	}
+
+	written, err := os.ReadFile(jsPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	page, err := os.ReadFile(index)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `integrity="` + sriHash(written) + `"`; !strings.Contains(string(page), want) {
+		t.Errorf("index.html should carry %s of the rewritten wasm_exec.js, got:\n%s", want, page)
+	}
+}
+
+func TestPreloadLinkTag(t *testing.T) {
+	w := New(&Config{
+		AppRootDir:              t.TempDir(),
+		OutputName:              "app",
+		DebugOutputSuffix:       ".debug",
+		DisableWasmExecJsOutput: true,
+		Logger:                  func(...any) {},
+	})
+
+	expected := `<link rel="preload" href="app.wasm" as="fetch" type="application/wasm" crossorigin>`
+	if got := w.PreloadLinkTag(); got != expected {
+		t.Errorf("PreloadLinkTag() = %s, want %s", got, expected)
+	}
+	if page := w.indexHTML("wasm_exec.js", ""); !strings.Contains(page, expected) {
+		t.Errorf("index.html should include the preload tag, got:\n%s", page)
+	}
+
+	w.updateCurrentBuilder(w.Config.BuildMediumSizeShortcut)
+	if got := w.PreloadLinkTag(); !strings.Contains(got, `href="app.debug.wasm"`) {
+		t.Errorf("PreloadLinkTag() = %s, should follow the active output name", got)
+	}
+}
+
+func TestInjectLoaderIntoHTML(t *testing.T) {
+	tmp := t.TempDir()
+	pagePath := filepath.Join(tmp, "web", "public", "index.html")
+	if err := os.MkdirAll(filepath.Dir(pagePath), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	original := "<!DOCTYPE html>\n<html>\n<HEAD>\n\t<title>My app</title>\n</HEAD>\n<body><h1>hi</h1></body>\n</html>\n"
+	if err := os.WriteFile(pagePath, []byte(original), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	w := New(&Config{
+		AppRootDir:          tmp,
+		SourceDir:           "web",
+		OutputDir:           "web/public",
+		WasmExecJsOutputDir: "web/js",
+		InjectIntoHTML:      "web/public/index.html",
+		Logger:              func(...any) {},
+	})
+	w.wasmProject = true
+
+	w.wasmProjectWriteOrReplaceWasmExecJsOutput()
+
+	page, err := os.ReadFile(pagePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := strings.Replace(original, "</HEAD>", "\t<script src=\"../js/wasm_exec.js\" defer></script>\n</HEAD>", 1)
+	if string(page) != expected {
+		t.Errorf("injected page =\n%s\nwant\n%s", page, expected)
+	}
+
+	// Idempotent: an existing loader tag is detected
+	if err := w.InjectLoaderIntoHTML(); err != nil {
+		t.Fatalf("InjectLoaderIntoHTML failed: %v", err)
+	}
+	if again, _ := os.ReadFile(pagePath); string(again) != expected {
+		t.Errorf("second injection changed the page:\n%s", again)
+	}
+
+	// Pages without <head> get the tag before </body>
+	if err := os.WriteFile(pagePath, []byte("<body><p>x</p></body>"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.InjectLoaderIntoHTML(); err != nil {
+		t.Fatalf("InjectLoaderIntoHTML failed: %v", err)
+	}
+	if page, _ := os.ReadFile(pagePath); string(page) != "<body><p>x</p><script src=\"../js/wasm_exec.js\" defer></script>\n</body>" {
+		t.Errorf("unexpected injection without head:\n%s", page)
+	}
+}
+
+func TestInlineBootstrapHTML(t *testing.T) {
+	tmp := t.TempDir()
+	writeWasmProject(t, tmp)
+
+	w := New(&Config{
+		AppRootDir:              tmp,
+		SourceDir:               "web",
+		OutputDir:               "web/public",
+		WasmExecJsOutputDir:     "web/js",
+		MainInputFile:           "main.go",
+		DisableWasmExecJsOutput: true,
+		Logger:                  func(...any) {},
+	})
+
+	snippet, err := w.InlineBootstrapHTML()
+	if err != nil {
+		t.Fatalf("InlineBootstrapHTML failed: %v", err)
+	}
+	if !strings.HasPrefix(snippet, "<script>") || !strings.HasSuffix(snippet, "</script>\n") {
+		t.Errorf("snippet should be a single inline script:\n%.200s", snippet)
+	}
+	if strings.Count(snippet, "</script") != 1 {
+		t.Error("the inline code must not close the script element early")
+	}
+	for _, expected := range []string{"// TinyWasm: mode=L", `fetch("main.wasm")`} {
+		if !strings.Contains(snippet, expected) {
+			t.Errorf("snippet missing %q", expected)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(tmp, "web", "js", "wasm_exec.js")); !os.IsNotExist(err) {
+		t.Error("InlineBootstrapHTML must not write wasm_exec.js")
+	}
+}
@@ -0,0 +1,193 @@
+package tinywasm
+
+import (
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// ignoreFileName is the gitignore-style file in AppRootDir listing files that
+// must not trigger compilation
+const ignoreFileName = ".tinywasmignore"
+
+// ignoreRule is one parsed .tinywasmignore pattern
+type ignoreRule struct {
+	pattern  string // slash separated, without leading "!" or "/"
+	negate   bool   // "!pattern" re-includes previously ignored files
+	anchored bool   // pattern contains a "/" and is matched from AppRootDir only
+	dirOnly  bool   // "pattern/" only matches directories (and their content)
+}
+
+// ignoreList caches the parsed .tinywasmignore with the modtime it was read at
+type ignoreList struct {
+	rules   []ignoreRule
+	modTime time.Time
+	loaded  bool
+}
+
+// IgnorePatterns returns the raw patterns of AppRootDir/.tinywasmignore so watchers
+// can apply the same exclusions
+func (w *TinyWasm) IgnorePatterns() []string {
+	w.reloadIgnoreFileIfChanged()
+
+	patterns := make([]string, 0, len(w.ignore.rules))
+	for _, r := range w.ignore.rules {
+		p := r.pattern
+		if r.anchored {
+			p = "/" + p
+		}
+		if r.dirOnly {
+			p += "/"
+		}
+		if r.negate {
+			p = "!" + p
+		}
+		patterns = append(patterns, p)
+	}
+	return patterns
+}
+
+// IsIgnored reports whether filePath is excluded from compile triggers by
+// AppRootDir/.tinywasmignore. Patterns follow .gitignore rules: "*", "?" and "**"
+// globs, "!" negation (last matching pattern wins), a leading or inner "/" anchors
+// the pattern to AppRootDir and a trailing "/" matches directories only.
+func (w *TinyWasm) IsIgnored(filePath string) bool {
+	w.reloadIgnoreFileIfChanged()
+	if len(w.ignore.rules) == 0 {
+		return false
+	}
+
+	rel := filePath
+	if filepath.IsAbs(filePath) {
+		root, err := filepath.Abs(w.Config.AppRootDir)
+		if err != nil {
+			return false
+		}
+		if rel, err = filepath.Rel(root, filePath); err != nil {
+			return false
+		}
+	}
+	rel = path.Clean(filepath.ToSlash(rel))
+	if strings.HasPrefix(rel, "../") {
+		return false // outside the project
+	}
+
+	ignored := false
+	for _, r := range w.ignore.rules {
+		if r.matches(rel) {
+			ignored = !r.negate
+		}
+	}
+	return ignored
+}
+
+// reloadIgnoreFileIfChanged parses .tinywasmignore on first use and again
+// whenever the file is modified, created or removed
+func (w *TinyWasm) reloadIgnoreFileIfChanged() {
+	var modTime time.Time
+	info, err := os.Stat(filepath.Join(w.Config.AppRootDir, ignoreFileName))
+	if err == nil {
+		modTime = info.ModTime()
+	}
+
+	if w.ignore.loaded && modTime.Equal(w.ignore.modTime) {
+		return
+	}
+
+	w.ignore = ignoreList{modTime: modTime, loaded: true}
+	if err != nil {
+		return
+	}
+
+	data, err := os.ReadFile(filepath.Join(w.Config.AppRootDir, ignoreFileName))
+	if err != nil {
+		w.Logger("Error reading", ignoreFileName+":", err)
+		return
+	}
+	w.ignore.rules = parseIgnoreRules(string(data))
+}
+
+// parseIgnoreRules parses gitignore-style content, skipping blanks and comments
+func parseIgnoreRules(content string) []ignoreRule {
+	var rules []ignoreRule
+	for _, line := range strings.Split(content, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		var r ignoreRule
+		if strings.HasPrefix(line, "!") {
+			r.negate = true
+			line = line[1:]
+		}
+		if strings.HasSuffix(line, "/") {
+			r.dirOnly = true
+			line = strings.TrimSuffix(line, "/")
+		}
+		if strings.Contains(line, "/") {
+			r.anchored = true
+			line = strings.TrimPrefix(line, "/")
+		}
+		if line == "" {
+			continue
+		}
+
+		r.pattern = line
+		rules = append(rules, r)
+	}
+	return rules
+}
+
+// matches reports whether the rule applies to rel (a clean slash separated path
+// relative to AppRootDir). Files inside a matched directory match too.
+func (r ignoreRule) matches(rel string) bool {
+	segments := strings.Split(rel, "/")
+	pattern := strings.Split(r.pattern, "/")
+
+	// Try the path itself and each of its parent directories
+	for end := len(segments); end > 0; end-- {
+		if r.dirOnly && end == len(segments) {
+			continue // the last segment is the file itself
+		}
+		candidate := segments[:end]
+		if r.anchored {
+			if matchSegments(pattern, candidate) {
+				return true
+			}
+			continue
+		}
+		// Unanchored patterns match at any depth
+		for start := 0; start < end; start++ {
+			if matchSegments(pattern, candidate[start:]) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// matchSegments matches path segments against pattern segments where "**"
+// matches zero or more whole segments
+func matchSegments(pattern, segments []string) bool {
+	if len(pattern) == 0 {
+		return len(segments) == 0
+	}
+	if pattern[0] == "**" {
+		for i := 0; i <= len(segments); i++ {
+			if matchSegments(pattern[1:], segments[i:]) {
+				return true
+			}
+		}
+		return false
+	}
+	if len(segments) == 0 {
+		return false
+	}
+	if ok, _ := path.Match(pattern[0], segments[0]); !ok {
+		return false
+	}
+	return matchSegments(pattern[1:], segments[1:])
+}
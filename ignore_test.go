@@ -0,0 +1,62 @@
+package tinywasm
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestTinyWasmIgnore(t *testing.T) {
+	tmp := t.TempDir()
+	ignoreContent := "# generated code\n" +
+		"*_gen.go\n" +
+		"vendor/\n" +
+		"/web/legacy/**/*.go\n" +
+		"!web/legacy/keep/main.wasm.go\n"
+	if err := os.WriteFile(filepath.Join(tmp, ignoreFileName), []byte(ignoreContent), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	w := New(&Config{
+		AppRootDir:              tmp,
+		DisableWasmExecJsOutput: true,
+		Logger:                  func(...any) {},
+	})
+
+	tests := []struct {
+		path    string
+		ignored bool
+	}{
+		{"web/models_gen.go", true},
+		{"models_gen.go", true},
+		{"vendor/lib/lib.go", true},
+		{"web/vendor/lib.go", true},
+		{"web/legacy/a/b/old.wasm.go", true},
+		{"web/legacy/old.wasm.go", true},
+		{"web/legacy/keep/main.wasm.go", false},
+		{"other/web/legacy/old.go", false}, // anchored pattern
+		{"web/main.wasm.go", false},
+		{filepath.Join(tmp, "web", "models_gen.go"), true}, // absolute paths
+	}
+	for _, tt := range tests {
+		if got := w.IsIgnored(tt.path); got != tt.ignored {
+			t.Errorf("IsIgnored(%q) = %v, want %v", tt.path, got, tt.ignored)
+		}
+	}
+
+	if w.ShouldCompileToWasm("old.wasm.go", filepath.Join(tmp, "web", "legacy", "old.wasm.go")) {
+		t.Error("ShouldCompileToWasm should reject ignored files")
+	}
+
+	// Changes to .tinywasmignore are picked up without a new instance
+	later := time.Now().Add(time.Second)
+	if err := os.WriteFile(filepath.Join(tmp, ignoreFileName), []byte("web/main.wasm.go\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	os.Chtimes(filepath.Join(tmp, ignoreFileName), later, later)
+
+	if !w.IsIgnored("web/main.wasm.go") || w.IsIgnored("web/models_gen.go") {
+		t.Error("expected rules to reload after .tinywasmignore changed")
+	}
+}
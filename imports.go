@@ -0,0 +1,63 @@
+package tinywasm
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	. "github.com/cdvelop/tinystring"
+)
+
+// ImportSnapshot returns the sorted list of packages the main package depends on
+// (including itself and the standard library), as reported by "go list -deps" for
+// GOOS=js GOARCH=wasm. Store it to compare later builds with ImportDelta.
+func (w *TinyWasm) ImportSnapshot() ([]string, error) {
+	pkg := "./" + filepath.ToSlash(w.Config.SourceDir)
+
+	cmd := exec.Command("go", "list", "-deps", pkg)
+	cmd.Dir = w.Config.AppRootDir
+	cmd.Env = append(os.Environ(), "GOOS=js", "GOARCH=wasm")
+
+	out, err := cmd.Output()
+	if err != nil {
+		if exitErr, ok := err.(*exec.ExitError); ok {
+			return nil, Err("go list -deps failed:", strings.TrimSpace(string(exitErr.Stderr)))
+		}
+		return nil, Err("go list -deps failed:", err)
+	}
+
+	var imports []string
+	for _, line := range strings.Split(string(out), "\n") {
+		if p := strings.TrimSpace(line); p != "" {
+			imports = append(imports, p)
+		}
+	}
+	slices.Sort(imports)
+	return slices.Compact(imports), nil
+}
+
+// ImportDelta compares the current dependency list (see ImportSnapshot) against a
+// previously stored snapshot and returns the packages that were added and removed,
+// so CI can flag heavy dependencies pulled in by a refactor before they bloat the wasm.
+func (w *TinyWasm) ImportDelta(previousSnapshot []string) (added, removed []string, err error) {
+	current, err := w.ImportSnapshot()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for _, p := range current {
+		if !slices.Contains(previousSnapshot, p) {
+			added = append(added, p)
+		}
+	}
+	for _, p := range previousSnapshot {
+		if !slices.Contains(current, p) && !slices.Contains(removed, p) {
+			removed = append(removed, p)
+		}
+	}
+	slices.Sort(removed)
+
+	return added, removed, nil
+}
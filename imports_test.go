@@ -0,0 +1,42 @@
+package tinywasm
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestImportDelta(t *testing.T) {
+	tmp := t.TempDir()
+	writeWasmProject(t, tmp)
+
+	w := New(&Config{
+		AppRootDir:              tmp,
+		SourceDir:               "web",
+		OutputDir:               "web/public",
+		DisableWasmExecJsOutput: true,
+		Logger:                  func(...any) {},
+	})
+
+	snapshot, err := w.ImportSnapshot()
+	if err != nil {
+		t.Fatalf("ImportSnapshot failed: %v", err)
+	}
+	if !slices.Contains(snapshot, "test/web") || !slices.Contains(snapshot, "runtime") {
+		t.Fatalf("snapshot should include the main package and runtime, got %v", snapshot)
+	}
+
+	// Drop a package and add a stale one to the stored snapshot
+	previous := slices.DeleteFunc(slices.Clone(snapshot), func(p string) bool { return p == "test/web" })
+	previous = append(previous, "github.com/heavy/dependency")
+
+	added, removed, err := w.ImportDelta(previous)
+	if err != nil {
+		t.Fatalf("ImportDelta failed: %v", err)
+	}
+	if !slices.Equal(added, []string{"test/web"}) {
+		t.Errorf("added = %v, want [test/web]", added)
+	}
+	if !slices.Equal(removed, []string{"github.com/heavy/dependency"}) {
+		t.Errorf("removed = %v, want [github.com/heavy/dependency]", removed)
+	}
+}
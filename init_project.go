@@ -0,0 +1,143 @@
+package tinywasm
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	. "github.com/cdvelop/tinystring"
+)
+
+// indexHTMLPath returns the path of the index.html written by InitProject in OutputDir
+func (w *TinyWasm) indexHTMLPath() string {
+	return filepath.Join(w.Config.AppRootDir, w.Config.OutputDir, "index.html")
+}
+
+// InitProject scaffolds a runnable wasm project under AppRootDir: a go.mod for
+// modulePath (when none exists), the default main file in SourceDir, wasm_exec.js and
+// an index.html in OutputDir loading it (skipped while a bundler is detected, see
+// DetectedBundler). Existing files are never overwritten, so it is safe to run on a
+// partially set up project. modulePath is only required when go.mod has to be created.
+func (w *TinyWasm) InitProject(modulePath string) error {
+	root := w.Config.AppRootDir
+
+	for _, dir := range []string{w.Config.SourceDir, w.Config.OutputDir} {
+		if err := os.MkdirAll(filepath.Join(root, dir), 0755); err != nil {
+			return Err("creating project directory:", err)
+		}
+	}
+
+	if err := w.writeGoModIfMissing(modulePath); err != nil {
+		return err
+	}
+
+	w.CreateDefaultWasmFileClientIfNotExist()
+	if _, err := os.Stat(filepath.Join(root, w.Config.SourceDir, w.Config.MainInputFile)); err != nil {
+		return Err("generating main file:", err)
+	}
+	w.wasmProject = true
+
+	if !w.Config.DisableWasmExecJsOutput {
+		if _, err := os.Stat(w.WasmExecJsOutputPath()); err != nil {
+			w.wasmProjectWriteOrReplaceWasmExecJsOutput()
+		}
+	}
+
+	if w.skipHTMLGeneration() {
+		w.Logger("Skipping index.html: pages are managed by", w.bundler)
+		return nil
+	}
+
+	indexPath := w.indexHTMLPath()
+	if _, err := os.Stat(indexPath); err == nil {
+		return nil
+	}
+
+	js, err := w.JavascriptForInitializing()
+	if err != nil {
+		return Err("generating wasm_exec.js:", err)
+	}
+
+	// The page lives in OutputDir next to the wasm, wasm_exec.js may be elsewhere
+	scriptSrc := "wasm_exec.js"
+	if rel, err := filepath.Rel(filepath.Dir(indexPath), w.WasmExecJsOutputPath()); err == nil {
+		scriptSrc = filepath.ToSlash(rel)
+	}
+
+	if err := os.WriteFile(indexPath, []byte(w.indexHTML(scriptSrc, js)), 0644); err != nil {
+		return Err("writing index.html:", err)
+	}
+	w.Logger("Created", indexPath)
+
+	return nil
+}
+
+// ScaffoldProject turns AppRootDir into a compilable wasm project: it creates SourceDir,
+// a go.mod for moduleName with the installed Go version, the default main file (see
+// GenerateWasmFile) and wasm_exec.js. Existing files are kept, so it is idempotent. Every
+// step runs even when another fails; the failures are returned joined. Unlike
+// InitProject no index.html is written.
+func (w *TinyWasm) ScaffoldProject(moduleName string) error {
+	var errs []error
+
+	if err := os.MkdirAll(filepath.Join(w.Config.AppRootDir, w.Config.SourceDir), 0755); err != nil {
+		errs = append(errs, Err("creating source directory:", err.Error()))
+	}
+
+	if err := w.writeGoModIfMissing(moduleName); err != nil {
+		errs = append(errs, err)
+	}
+
+	if err := w.GenerateWasmFile(false); err != nil {
+		errs = append(errs, err)
+	} else if _, err := os.Stat(w.mainInputPath()); err == nil {
+		w.wasmProject = true // kept from a previous run
+	}
+
+	if w.wasmProject && !w.Config.DisableWasmExecJsOutput && !w.skipsWasmExecJs(w.Value()) {
+		if _, err := os.Stat(w.WasmExecJsOutputPath()); err != nil {
+			if err := w.writeWasmExecJs(); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// writeGoModIfMissing writes a go.mod for modulePath in AppRootDir unless one exists.
+// modulePath is only required when the file has to be created.
+func (w *TinyWasm) writeGoModIfMissing(modulePath string) error {
+	goModPath := filepath.Join(w.Config.AppRootDir, "go.mod")
+	if _, err := os.Stat(goModPath); err == nil {
+		return nil
+	}
+	if strings.TrimSpace(modulePath) == "" {
+		return Err("module path required to create", goModPath)
+	}
+	content := "module " + strings.TrimSpace(modulePath) + "\n\ngo " + goModVersion() + "\n"
+	if err := os.WriteFile(goModPath, []byte(content), 0644); err != nil {
+		return Err("writing go.mod:", err.Error())
+	}
+	w.Logger("Created", goModPath)
+	return nil
+}
+
+// goModVersion returns the "go" directive for a new go.mod: the installed Go
+// version, or the one this tool was built with when go is not available
+func goModVersion() string {
+	version, err := toolchainVersion("go")
+	if err != nil {
+		version = runtime.Version()
+	}
+	version = strings.TrimPrefix(version, "go")
+	if i := strings.IndexAny(version, " -"); i >= 0 {
+		version = version[:i] // eg: "1.26-devel_abc" or "1.25.2 X:exp"
+	}
+	if version == "" || version[0] < '0' || version[0] > '9' {
+		return "1.21" // unreleased toolchain: oldest version with toolchain support in go.mod
+	}
+	return version
+}
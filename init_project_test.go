@@ -0,0 +1,111 @@
+package tinywasm
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestInitProject(t *testing.T) {
+	tmp := t.TempDir()
+	w := New(&Config{
+		AppRootDir:          tmp,
+		SourceDir:           "web",
+		OutputDir:           "web/public",
+		WasmExecJsOutputDir: "web/js",
+		MainInputFile:       "main.go",
+		Logger:              func(...any) {},
+	})
+
+	if err := w.InitProject(""); err == nil {
+		t.Error("expected an error without module path when go.mod is missing")
+	}
+
+	if err := w.InitProject("example.com/hello"); err != nil {
+		t.Fatalf("InitProject failed: %v", err)
+	}
+	if !w.wasmProject {
+		t.Error("expected wasmProject to be true after InitProject")
+	}
+
+	goMod, err := os.ReadFile(filepath.Join(tmp, "go.mod"))
+	if err != nil {
+		t.Fatalf("go.mod not created: %v", err)
+	}
+	if !strings.HasPrefix(string(goMod), "module example.com/hello\n\ngo 1.") {
+		t.Errorf("unexpected go.mod:\n%s", goMod)
+	}
+
+	for _, file := range []string{"web/main.go", "web/js/wasm_exec.js"} {
+		if _, err := os.Stat(filepath.Join(tmp, file)); err != nil {
+			t.Errorf("expected %s to be created: %v", file, err)
+		}
+	}
+
+	page, err := os.ReadFile(filepath.Join(tmp, "web", "public", "index.html"))
+	if err != nil {
+		t.Fatalf("index.html not created: %v", err)
+	}
+	if !strings.Contains(string(page), `<script src="../js/wasm_exec.js" defer></script>`) {
+		t.Errorf("index.html should load wasm_exec.js relative to the page, got:\n%s", page)
+	}
+
+	// Existing files are kept and go.mod is not required anymore
+	custom := []byte("<h1>custom</h1>")
+	if err := os.WriteFile(filepath.Join(tmp, "web", "public", "index.html"), custom, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.InitProject(""); err != nil {
+		t.Fatalf("second InitProject failed: %v", err)
+	}
+	if page, _ := os.ReadFile(filepath.Join(tmp, "web", "public", "index.html")); string(page) != string(custom) {
+		t.Error("existing index.html should not be overwritten")
+	}
+}
+
+// TestScaffoldProject verifies an empty folder becomes a compilable project and a second
+// run keeps the existing files
+func TestScaffoldProject(t *testing.T) {
+	tmp := t.TempDir()
+	w := New(&Config{
+		AppRootDir:          tmp,
+		SourceDir:           "web",
+		OutputDir:           "web/public",
+		WasmExecJsOutputDir: "web/js",
+		Logger:              func(...any) {},
+	})
+
+	if err := w.ScaffoldProject(""); err == nil || !strings.Contains(err.Error(), "module path required") {
+		t.Errorf("ScaffoldProject(\"\") = %v, want the go.mod error", err)
+	}
+	if _, err := os.Stat(filepath.Join(tmp, "web", "main.go")); err != nil {
+		t.Errorf("the other steps should run despite the go.mod error: %v", err)
+	}
+
+	if err := w.ScaffoldProject("example.com/hello"); err != nil {
+		t.Fatalf("ScaffoldProject failed: %v", err)
+	}
+	for _, file := range []string{"go.mod", "web/main.go", "web/js/wasm_exec.js"} {
+		if _, err := os.Stat(filepath.Join(tmp, file)); err != nil {
+			t.Errorf("expected %s to be created: %v", file, err)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(tmp, "web", "public", "index.html")); err == nil {
+		t.Error("ScaffoldProject should not write index.html")
+	}
+
+	main := filepath.Join(tmp, "web", "main.go")
+	if err := os.WriteFile(main, []byte("// edited"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.ScaffoldProject("example.com/other"); err != nil {
+		t.Fatalf("second ScaffoldProject failed: %v", err)
+	}
+	if content, _ := os.ReadFile(main); string(content) != "// edited" {
+		t.Errorf("main file overwritten: %s", content)
+	}
+	if goMod, _ := os.ReadFile(filepath.Join(tmp, "go.mod")); !strings.HasPrefix(string(goMod), "module example.com/hello\n") {
+		t.Errorf("go.mod overwritten:\n%s", goMod)
+	}
+}
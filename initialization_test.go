@@ -55,6 +55,11 @@ func TestInitializationDetectionFromWasmExecJs(t *testing.T) {
 	if tinyWasm.currentMode != config.BuildLargeSizeShortcut {
 		t.Errorf("Expected currentMode to be %s, got %s", config.BuildLargeSizeShortcut, tinyWasm.currentMode)
 	}
+
+	expected := DetectionReport{IsWasmProject: true, Source: DetectionSourceWasmExecJs, Compiler: "go", Mode: "L"}
+	if report := tinyWasm.DetectionReport(); report != expected {
+		t.Errorf("DetectionReport() = %+v, want %+v", report, expected)
+	}
 }
 
 // TestInitializationDetectionFromGoFiles tests detection from .wasm.go files
@@ -120,6 +125,11 @@ func TestInitializationDetectionFromGoFiles(t *testing.T) {
 	if !found {
 		t.Fatalf("Expected generated wasm_exec.js to include Go signatures, none found")
 	}
+
+	expected := DetectionReport{IsWasmProject: true, Source: DetectionSourceGoFiles, Compiler: "go", Mode: "L"}
+	if report := tinyWasm.DetectionReport(); report != expected {
+		t.Errorf("DetectionReport() = %+v, want %+v", report, expected)
+	}
 }
 
 // TestDefaultConfiguration tests that WasmExecJsOutputDir defaults to "src/web/ui/js"
@@ -172,6 +182,9 @@ func TestNoWasmProjectDetected(t *testing.T) {
 	if tinyWasm.wasmProject {
 		t.Error("Expected wasmProject to be false initially when no WASM files exist")
 	}
+	if report := tinyWasm.DetectionReport(); report.IsWasmProject || report.Source != DetectionSourceNone {
+		t.Errorf("DetectionReport() = %+v, want no project detected", report)
+	}
 
 	// Now create the default WASM file using the new optional method
 	result := tinyWasm.CreateDefaultWasmFileClientIfNotExist()
@@ -190,3 +203,218 @@ func TestNoWasmProjectDetected(t *testing.T) {
 		t.Errorf("Expected default WASM file to be created at %s", expectedPath)
 	}
 }
+
+// TestDetectionOrderGoFilesWinOverStaleWasmExecJs verifies Config.DetectionOrder lets
+// .go files take precedence over a stale committed TinyGo wasm_exec.js
+func TestDetectionOrderGoFilesWinOverStaleWasmExecJs(t *testing.T) {
+	newProject := func(order []string) *TinyWasm {
+		testDir := t.TempDir()
+		jsDir := filepath.Join(testDir, "web", "theme", "js")
+		if err := os.MkdirAll(jsDir, 0755); err != nil {
+			t.Fatalf("Failed to create test directories: %v", err)
+		}
+		stale := wasmExecJsHeader("M") + strings.Join(wasm_execTinyGoSignatures(), "\n")
+		if err := os.WriteFile(filepath.Join(jsDir, "wasm_exec.js"), []byte(stale), 0644); err != nil {
+			t.Fatalf("Failed to create test wasm_exec.js: %v", err)
+		}
+		if err := os.WriteFile(filepath.Join(testDir, "web", "main.wasm.go"), []byte("package main\n\nfunc main() {}\n"), 0644); err != nil {
+			t.Fatalf("Failed to create main file: %v", err)
+		}
+
+		return New(&Config{
+			AppRootDir:          testDir,
+			SourceDir:           "web",
+			OutputDir:           "web/public",
+			WasmExecJsOutputDir: "web/theme/js",
+			DetectionOrder:      order,
+			Logger:              func(message ...any) {},
+		})
+	}
+
+	// Default: the committed wasm_exec.js decides
+	if w := newProject(nil); !w.tinyGoCompiler || w.Value() != "M" {
+		t.Errorf("default order: expected TinyGo in mode M from wasm_exec.js, got tinyGo=%v mode=%s", w.tinyGoCompiler, w.Value())
+	}
+
+	// .go files first: the stale wasm_exec.js is regenerated for the Go compiler
+	w := newProject([]string{DetectGoFiles, DetectJsSignatures})
+	if !w.wasmProject || w.tinyGoCompiler {
+		t.Errorf("go_files first: expected Go WASM project, got wasmProject=%v tinyGo=%v", w.wasmProject, w.tinyGoCompiler)
+	}
+	content, err := os.ReadFile(w.WasmExecJsOutputPath())
+	if err != nil {
+		t.Fatalf("reading wasm_exec.js: %v", err)
+	}
+	if tinyGo, ok := classifyWasmExecJs(string(content)); !ok || tinyGo {
+		t.Error("expected wasm_exec.js to be regenerated with the Go runtime")
+	}
+
+	if err := w.validateDetectionOrder(); err != nil {
+		t.Errorf("valid order rejected: %v", err)
+	}
+	w.Config.DetectionOrder = []string{"html_files"}
+	if err := w.validateDetectionOrder(); err == nil {
+		t.Error("expected unknown detection source to be rejected")
+	}
+}
+
+// TestDetectionMaxDepth verifies Config.DetectionMaxDepth bounds the .go file walk
+func TestDetectionMaxDepth(t *testing.T) {
+	testDir := t.TempDir()
+	deepDir := filepath.Join(testDir, "src", "cmd", "webclient", "modules", "users", "ui")
+	if err := os.MkdirAll(deepDir, 0755); err != nil {
+		t.Fatalf("Failed to create test directories: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(deepDir, "users.wasm.go"), []byte("package ui\n"), 0644); err != nil {
+		t.Fatalf("Failed to create wasm file: %v", err)
+	}
+
+	w := New(&Config{
+		AppRootDir:              testDir,
+		SourceDir:               "src/cmd/webclient",
+		OutputDir:               "src/web/public",
+		DisableWasmExecJsOutput: true,
+		Logger:                  func(message ...any) {},
+	})
+
+	// users.wasm.go is 3 levels below SourceDir; the path to SourceDir is always walked
+	for depth, want := range map[int]bool{0: true, 2: false, 3: true} {
+		w.Config.DetectionMaxDepth = depth
+		if got := w.detectFromGoFiles(); got != want {
+			t.Errorf("DetectionMaxDepth=%d: detectFromGoFiles() = %v, want %v", depth, got, want)
+		}
+	}
+}
+
+// TestDetectionIgnoreDirs verifies the .go file walk skips dependency and build directories
+func TestDetectionIgnoreDirs(t *testing.T) {
+	testDir := t.TempDir()
+
+	w := New(&Config{
+		AppRootDir:              testDir,
+		SourceDir:               "web",
+		OutputDir:               "web/public",
+		DisableWasmExecJsOutput: true,
+		Logger:                  func(message ...any) {},
+	})
+
+	for _, dir := range []string{"node_modules/pkg", "vendor/example.com/mod", ".git/hooks", "dist", "testdata"} {
+		if err := os.MkdirAll(filepath.Join(testDir, dir), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(testDir, dir, "lib.wasm.go"), []byte("package lib\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	w.Config.DetectionIgnoreDirs = []string{"testdata"}
+
+	if w.detectFromGoFiles() {
+		t.Error("wasm files in ignored directories should not be detected")
+	}
+
+	// Legitimate source dirs are still walked
+	if err := os.MkdirAll(filepath.Join(testDir, "web"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(testDir, "web", "main.go"), []byte("package main\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if !w.detectFromGoFiles() {
+		t.Error("main file in SourceDir should be detected")
+	}
+}
+
+// TestAppRootDirResolvedToAbsolute verifies a relative AppRootDir is fixed at New,
+// so a later working directory change doesn't move the outputs
+func TestAppRootDirResolvedToAbsolute(t *testing.T) {
+	testDir := t.TempDir()
+	t.Chdir(testDir)
+
+	w := New(&Config{
+		AppRootDir:              ".",
+		OutputDir:               "web/public",
+		DisableWasmExecJsOutput: true,
+		Logger:                  func(message ...any) {},
+	})
+
+	root, err := filepath.Abs(testDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if w.Config.AppRootDir != root {
+		t.Errorf("AppRootDir = %q, want %q", w.Config.AppRootDir, root)
+	}
+
+	t.Chdir(t.TempDir())
+	if got, want := w.MainOutputFileAbsolutePath(), filepath.Join(root, "web", "public", "main.wasm"); got != want {
+		t.Errorf("MainOutputFileAbsolutePath() = %q, want %q", got, want)
+	}
+}
+
+// TestReset verifies Reset re-targets detection and builders to a new AppRootDir
+func TestReset(t *testing.T) {
+	empty := t.TempDir()
+	project := t.TempDir()
+	writeWasmProject(t, project)
+
+	w := New(&Config{
+		AppRootDir:              empty,
+		SourceDir:               "web",
+		OutputDir:               "web/public",
+		DisableWasmExecJsOutput: true,
+		Logger:                  func(...any) {},
+	})
+	if w.DetectionReport().IsWasmProject {
+		t.Fatal("no project should be detected in an empty directory")
+	}
+
+	w.Config.AppRootDir = project
+	w.Reset()
+
+	if report := w.DetectionReport(); !report.IsWasmProject || report.Source != DetectionSourceGoFiles {
+		t.Errorf("DetectionReport() after Reset = %+v, want project detected from go files", report)
+	}
+	want := filepath.Join(project, "web", "public", "main.wasm")
+	if got := w.activeBuilder.FinalOutputPath(); got != want {
+		t.Errorf("output after Reset = %s, want %s", got, want)
+	}
+}
+
+// TestSetAppRootDir verifies switching projects and rejecting a missing directory
+func TestSetAppRootDir(t *testing.T) {
+	project := t.TempDir()
+	writeWasmProject(t, project)
+
+	w := New(&Config{
+		AppRootDir:              t.TempDir(),
+		SourceDir:               "web",
+		OutputDir:               "web/public",
+		DisableWasmExecJsOutput: true,
+		Logger:                  func(...any) {},
+	})
+
+	if err := w.SetAppRootDir(filepath.Join(project, "missing")); err == nil {
+		t.Error("SetAppRootDir should fail for a missing directory")
+	}
+	if err := w.SetAppRootDir(filepath.Join(project, "go.mod")); err == nil {
+		t.Error("SetAppRootDir should fail for a file")
+	}
+
+	// State of the previous project
+	w.recordOutputSize(w.Value(), 1024)
+	w.diagnostics = []Diagnostic{{File: "main.go", Line: 1, Message: "undefined: x"}}
+	w.history.add(BuildResult{Mode: "L"}, 10)
+
+	if err := w.SetAppRootDir(project); err != nil {
+		t.Fatalf("SetAppRootDir failed: %v", err)
+	}
+	if w.Config.AppRootDir != project || !w.DetectionReport().IsWasmProject {
+		t.Errorf("AppRootDir = %s, detection %+v: want %s detected", w.Config.AppRootDir, w.DetectionReport(), project)
+	}
+	if got := w.activeBuilder.MainInputFileRelativePath(); got != filepath.Join(project, "web", "main.go") {
+		t.Errorf("builder input = %s, want the new project's main.go", got)
+	}
+	if w.LastOutputSize() != 0 || len(w.LastDiagnostics()) != 0 || len(w.BuildHistory(0)) != 0 {
+		t.Error("SetAppRootDir should drop the output size, diagnostics and history of the previous project")
+	}
+}
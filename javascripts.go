@@ -44,7 +44,9 @@ func (w *TinyWasm) WasmExecJsOutputPath() string {
 }
 
 // getWasmExecContent returns the raw wasm_exec.js content for the current compiler configuration.
-// This method returns the unmodified content from embedded assets without any headers or caching.
+// This method returns the unmodified content from embedded assets (or from
+// Config.CustomWasmExecGoPath/CustomWasmExecTinyGoPath when set, or from the installed
+// toolchain with Config.PreferToolchainWasmExec) without any headers or caching.
 // It relies on TinyWasm's internal state (via WasmProjectTinyGoJsUse) to determine which
 // compiler (Go vs TinyGo) to use.
 //
@@ -62,11 +64,41 @@ func (w *TinyWasm) getWasmExecContent(mode string) ([]byte, error) {
 		return nil, Errf("not a WASM project")
 	}
 
-	// Return appropriate embedded content based on compiler configuration
+	custom, embedded := w.Config.CustomWasmExecGoPath, embeddedWasmExecGo
 	if useTinyGo {
-		return embeddedWasmExecTinyGo, nil
+		custom, embedded = w.Config.CustomWasmExecTinyGoPath, embeddedWasmExecTinyGo
+	}
+
+	// A patched runtime maintained by the project replaces the embedded asset
+	if custom != "" {
+		if !filepath.IsAbs(custom) {
+			custom = filepath.Join(w.Config.AppRootDir, custom)
+		}
+		data, err := os.ReadFile(custom)
+		if err == nil {
+			return data, nil
+		}
+		w.Logger("Warning: custom wasm_exec.js not available, using the embedded one:", err)
 	}
-	return embeddedWasmExecGo, nil
+
+	// The runtime of the installed toolchain always matches the compiler
+	if w.Config.PreferToolchainWasmExec {
+		toolchainPath, err := w.GetWasmExecJsPathGo()
+		if useTinyGo {
+			toolchainPath, err = w.GetWasmExecJsPathTinyGo()
+		}
+		if err == nil {
+			var data []byte
+			if data, err = os.ReadFile(toolchainPath); err == nil {
+				w.Logger("DEBUG: Using toolchain wasm_exec.js:", toolchainPath)
+				return data, nil
+			}
+		}
+		w.Logger("Warning: toolchain wasm_exec.js not available, using the embedded one:", err)
+	}
+
+	// Return appropriate embedded content based on compiler configuration
+	return embedded, nil
 }
 
 // JavascriptForInitializing returns the JavaScript code needed to initialize WASM.
@@ -104,14 +136,16 @@ func (h *TinyWasm) JavascriptForInitializing(customizations ...string) (js strin
 		header = customizations[0]
 	} else {
 		// Default header: minimal comment with current mode for detection
-		currentModeAtGeneration := h.Value()
-		header = fmt.Sprintf("// TinyWasm: mode=%s\n", currentModeAtGeneration)
+		header = wasmExecJsHeader(h.Value())
 	}
 
 	stringWasmJs = header + stringWasmJs
+	if h.isReactor() {
+		stringWasmJs = header // reactor modules don't use the wasm_exec.js runtime
+	}
 
 	// Verify activeBuilder is initialized before accessing it
-	if h.activeBuilder == nil {
+	if h.currentBuilder() == nil {
 		return "", Errf("activeBuilder not initialized")
 	}
 
@@ -119,14 +153,12 @@ func (h *TinyWasm) JavascriptForInitializing(customizations ...string) (js strin
 	var footer string
 	if len(customizations) > 1 {
 		footer = customizations[1]
+	} else if h.isReactor() {
+		// Reactor modules have no Go runtime to run
+		footer = h.reactorJsFooter()
 	} else {
 		// Default footer: WebAssembly initialization code
-		footer = `
-		const go = new Go();
-		WebAssembly.instantiateStreaming(fetch("` + h.activeBuilder.MainOutputFileNameWithExtension() + `"), go.importObject).then((result) => {
-			go.run(result.instance);
-		});
-	`
+		footer = h.defaultJsFooter()
 	}
 	stringWasmJs += footer
 
@@ -154,6 +186,32 @@ func (h *TinyWasm) JavascriptForInitializing(customizations ...string) (js strin
 	return normalized, nil
 }
 
+// defaultJsFooter returns the WebAssembly initialization code appended after
+// wasm_exec.js. When Config.JSNamespace is set the Go instance lives under that
+// namespace object instead of a global, so several modules can share a page.
+func (h *TinyWasm) defaultJsFooter() string {
+	wasmFile := h.wasmFileName()
+	goRef, declaration := h.goInstanceJs()
+
+	return `
+		` + declaration + `
+		` + h.instantiateJs(wasmFile, goRef+".importObject") + `.then((result) => {
+			` + goRef + `.run(result.instance);
+		});
+	`
+}
+
+// goInstanceJs returns the JS expression referencing the Go instance and the
+// statement declaring it, honoring Config.JSNamespace.
+func (h *TinyWasm) goInstanceJs() (goRef, declaration string) {
+	ns := h.Config.JSNamespace
+	if ns == "" {
+		return "go", "const go = new Go();"
+	}
+	goRef = ns + ".go"
+	return goRef, "globalThis." + ns + " = globalThis." + ns + " || {};\n\t\t" + goRef + " = new Go();"
+}
+
 // normalizeJs applies deterministic normalization to JS content so cached
 // and regenerated outputs are identical: convert CRLF to LF and trim trailing
 // whitespace from each line.
@@ -179,6 +237,11 @@ func (h *TinyWasm) ClearJavaScriptCache() {
 
 // GetWasmExecJsPathTinyGo returns the path to TinyGo's wasm_exec.js file
 func (w *TinyWasm) GetWasmExecJsPathTinyGo() (string, error) {
+	// Method 0: User configured search paths take precedence
+	if found, ok := w.findWasmExecJsInSearchPaths(true); ok {
+		return found, nil
+	}
+
 	// Method 1: Try standard lib location pattern
 	libPaths := []string{
 		"/usr/local/lib/tinygo/targets/wasm_exec.js",
@@ -194,7 +257,7 @@ func (w *TinyWasm) GetWasmExecJsPathTinyGo() (string, error) {
 	// Method 2: Derive from tinygo executable path
 	tinygoPath, err := exec.LookPath("tinygo")
 	if err != nil {
-		return "", Errf("tinygo executable not found: %v", err)
+		return "", Errf("tinygo executable not found: %v (search paths: %v)", err, w.Config.WasmExecJsSearchPaths)
 	}
 
 	// Get directory where tinygo is located
@@ -216,11 +279,16 @@ func (w *TinyWasm) GetWasmExecJsPathTinyGo() (string, error) {
 		}
 	}
 
-	return "", Errf("TinyGo wasm_exec.js not found. Searched paths: %v", append(libPaths, patterns...))
+	return "", Errf("TinyGo wasm_exec.js not found. Searched paths: %v", append(append(w.Config.WasmExecJsSearchPaths, libPaths...), patterns...))
 }
 
 // GetWasmExecJsPathGo returns the path to Go's wasm_exec.js file
 func (w *TinyWasm) GetWasmExecJsPathGo() (string, error) {
+	// Method 0: User configured search paths take precedence
+	if found, ok := w.findWasmExecJsInSearchPaths(false); ok {
+		return found, nil
+	}
+
 	// Method 1: Try GOROOT environment variable (most reliable)
 	goRoot := os.Getenv("GOROOT")
 	if goRoot != "" {
@@ -238,7 +306,7 @@ func (w *TinyWasm) GetWasmExecJsPathGo() (string, error) {
 	// Method 2: Derive from go executable path
 	goPath, err := exec.LookPath("go")
 	if err != nil {
-		return "", Errf("go executable not found: %v", err)
+		return "", Errf("go executable not found: %v (search paths: %v)", err, w.Config.WasmExecJsSearchPaths)
 	}
 
 	// Get installation directory (parent of bin directory)
@@ -261,7 +329,7 @@ func (w *TinyWasm) GetWasmExecJsPathGo() (string, error) {
 		}
 	}
 
-	return "", Errf("go wasm_exec.js not found. Searched: GOROOT=%s, patterns=%v", goRoot, patterns)
+	return "", Errf("go wasm_exec.js not found. Searched: search paths=%v, GOROOT=%s, patterns=%v", w.Config.WasmExecJsSearchPaths, goRoot, patterns)
 }
 
 // getModeFromWasmExecJsHeader extracts the mode shortcut from a wasm_exec.js
@@ -304,12 +372,90 @@ func (h *TinyWasm) getModeFromWasmExecJsHeader(content string) (string, bool) {
 	return "", false
 }
 
+// wasmExecJsHeader returns the default first line written to wasm_exec.js,
+// used by getModeFromWasmExecJsHeader to restore the mode on startup.
+func wasmExecJsHeader(mode string) string {
+	return fmt.Sprintf("// TinyWasm: mode=%s\n", mode)
+}
+
+// sameWasmExecJsRuntime reports whether two modes generate the same
+// wasm_exec.js body: both use the same compiler runtime and load the same
+// output file, so only the mode header differs between them.
+func (w *TinyWasm) sameWasmExecJsRuntime(fromMode, toMode string) bool {
+	if w.requiresTinyGo(fromMode) != w.requiresTinyGo(toMode) {
+		return false
+	}
+	return w.builderForMode(fromMode).MainOutputFileNameWithExtension() ==
+		w.builderForMode(toMode).MainOutputFileNameWithExtension()
+}
+
+// updateWasmExecJsHeader rewrites only the mode header line of the existing
+// wasm_exec.js, keeping the rest of the file untouched. It returns false when
+// the file is missing or has no TinyWasm header so callers can fall back to a
+// full regeneration via wasmProjectWriteOrReplaceWasmExecJsOutput. The pages'
+// integrity hash follows the new content (see refreshLoaderIntegrity).
+func (w *TinyWasm) updateWasmExecJsHeader(mode string) bool {
+	if !w.wasmProject {
+		return false
+	}
+
+	outputPath := w.WasmExecJsOutputPath()
+	data, err := os.ReadFile(outputPath)
+	if err != nil {
+		return false
+	}
+
+	content := string(data)
+	if _, found := w.getModeFromWasmExecJsHeader(content); !found {
+		return false
+	}
+
+	body := ""
+	if idx := strings.Index(content, "\n"); idx != -1 {
+		body = content[idx+1:]
+	}
+
+	updated := wasmExecJsHeader(mode) + body
+	if err := os.WriteFile(outputPath, []byte(updated), 0644); err != nil {
+		w.Logger("Failed to update wasm_exec.js header:", err)
+		return false
+	}
+	w.refreshLoaderIntegrity(updated)
+
+	w.Logger("DEBUG: Updated wasm_exec.js header to mode", mode)
+	w.notifyWasmExecJsWritten(outputPath, mode)
+	return true
+}
+
+// ErrWasmExecJsOutputDisabled is returned by WriteWasmExecJs when
+// Config.DisableWasmExecJsOutput is set
+var ErrWasmExecJsOutputDisabled = Err("wasm_exec.js output disabled by Config.DisableWasmExecJsOutput")
+
+// WriteWasmExecJs (re)writes wasm_exec.js for the current mode without compiling, eg:
+// after a clean step removed it while the wasm output is still valid. Unlike the
+// automatic writes it reports failures, and ErrWasmExecJsOutputDisabled when the
+// output is disabled. The file is left untouched when already up to date.
+func (w *TinyWasm) WriteWasmExecJs() error {
+	if w.Config.DisableWasmExecJsOutput {
+		return ErrWasmExecJsOutputDisabled
+	}
+	if !w.wasmProject {
+		return Errf("not a WASM project: no main file %s or *.wasm.go files found", w.mainInputPath())
+	}
+	if w.isReactor() {
+		return Errf("wasm_exec.js is not used by %s modules", WasmABIReactor)
+	}
+	if w.skipsWasmExecJs(w.Value()) {
+		return Err("wasm_exec.js is not used by the TinyGo target", w.tinyGoTarget())
+	}
+	return w.writeWasmExecJs()
+}
+
 // wasmProjectWriteOrReplaceWasmExecJsOutput writes (or overwrites) the
 // wasm_exec.js initialization file into the configured web output folder for
 // WASM projects. If the receiver is not a WASM project the function returns
-// false immediately. On success or on any write attempt it returns true; any
-// filesystem or generation errors are logged via w.Logger and treated as
-// non-fatal so callers can continue their workflow.
+// immediately. Any filesystem or generation errors are logged via w.Logger and
+// treated as non-fatal so callers can continue their workflow.
 func (w *TinyWasm) wasmProjectWriteOrReplaceWasmExecJsOutput() {
 	// Only perform actions for recognized WASM projects
 	if !w.wasmProject {
@@ -317,6 +463,20 @@ func (w *TinyWasm) wasmProjectWriteOrReplaceWasmExecJsOutput() {
 		return
 	}
 
+	// Reactor modules and WASI targets don't use the Go runtime shipped in wasm_exec.js
+	if w.skipsWasmExecJs(w.Value()) {
+		w.Logger("DEBUG: Reactor module or WASI target, skipping wasm_exec.js write")
+		return
+	}
+
+	if err := w.writeWasmExecJs(); err != nil {
+		w.Logger(err)
+	}
+}
+
+// writeWasmExecJs writes wasm_exec.js (and the files generated alongside it) to
+// WasmExecJsOutputPath, skipping the write when the file on disk is already up to date
+func (w *TinyWasm) writeWasmExecJs() error {
 	outputPath := w.WasmExecJsOutputPath()
 
 	w.Logger("DEBUG: Writing/overwriting wasm_exec.js to output path:", outputPath)
@@ -324,43 +484,51 @@ func (w *TinyWasm) wasmProjectWriteOrReplaceWasmExecJsOutput() {
 	// Create output directory if it doesn't exist
 	outputDir := filepath.Dir(outputPath)
 	if err := os.MkdirAll(outputDir, 0755); err != nil {
-		w.Logger("Failed to create output directory:", err)
-		return // We did attempt the operation (project), but treat errors as non-fatal
+		return Err("Failed to create output directory:", err)
+	}
+
+	if w.Config.GenerateWorker {
+		w.writeWorkerBootstrap()
+	}
+
+	if err := w.InjectLoaderIntoHTML(); err != nil {
+		w.Logger("Failed to inject wasm_exec.js into HTML:", err)
 	}
 
 	// Get the complete JavaScript initialization code (includes WASM setup)
 	jsContent, err := w.JavascriptForInitializing()
 	if err != nil {
-		w.Logger("Failed to generate JavaScript initialization code:", err)
-		return
+		return Err("Failed to generate JavaScript initialization code:", err)
 	}
+	w.refreshLoaderIntegrity(jsContent)
 
-	// Write the complete JavaScript to output location, always overwrite
+	// Skip the write when the file on disk is already up to date
+	if existing, err := os.ReadFile(outputPath); err == nil && string(existing) == jsContent {
+		w.Logger("DEBUG: wasm_exec.js already up to date, skipping write")
+		return nil
+	}
+
+	// Write the complete JavaScript to output location, overwriting any previous content
 	if err := os.WriteFile(outputPath, []byte(jsContent), 0644); err != nil {
-		w.Logger("Failed to write JavaScript initialization file:", err)
-		return
+		return Err("Failed to write JavaScript initialization file:", err)
 	}
 
 	w.Logger("DEBUG: Wrote/overwrote JavaScript initialization file in output directory")
+	w.notifyWasmExecJsWritten(outputPath, w.Value())
+	return nil
 }
 
-// analyzeWasmExecJsContent analyzes existing wasm_exec.js to determine compiler type
-func (w *TinyWasm) analyzeWasmExecJsContent(filePath string) bool {
-	data, err := os.ReadFile(filePath)
-	if err != nil {
-		w.Logger("Error reading wasm_exec.js for detection:", err)
-		return false
-	}
-
-	content := string(data)
-
-	// PRIORITY 1: Extract mode from header if present
-	if mode, found := w.getModeFromWasmExecJsHeader(content); found {
-		w.currentMode = mode
-		//w.Logger("DEBUG: Restored mode from header:", mode)
+// notifyWasmExecJsWritten invokes Config.OnWasmExecJsWritten after an actual write of wasm_exec.js
+func (w *TinyWasm) notifyWasmExecJsWritten(outputPath, mode string) {
+	if w.Config.OnWasmExecJsWritten != nil {
+		w.Config.OnWasmExecJsWritten(outputPath, mode)
 	}
+}
 
-	// Count signatures (reuse existing logic from wasmDetectionFuncFromJsFileActive)
+// classifyWasmExecJs infers which compiler a wasm_exec.js belongs to by counting
+// the Go and TinyGo runtime signatures it contains. ok is false when no known
+// signature is present.
+func classifyWasmExecJs(content string) (tinyGo bool, ok bool) {
 	goCount := 0
 	for _, s := range wasm_execGoSignatures() {
 		if Contains(content, s) {
@@ -375,38 +543,93 @@ func (w *TinyWasm) analyzeWasmExecJsContent(filePath string) bool {
 		}
 	}
 
-	// Determine configuration based on signatures
-	if tinyCount > goCount && tinyCount > 0 {
-		w.tinyGoCompiler = true
-		w.wasmProject = true
-		//w.Logger("DEBUG: Detected TinyGo compiler from wasm_exec.js")
-	} else if goCount > tinyCount && goCount > 0 {
-		w.tinyGoCompiler = false
-		w.wasmProject = true
-		//w.Logger("DEBUG: Detected Go compiler from wasm_exec.js")
-	} else if tinyCount > 0 || goCount > 0 {
+	switch {
+	case tinyCount > goCount && tinyCount > 0:
+		return true, true
+	case goCount > tinyCount && goCount > 0:
+		return false, true
+	case tinyCount > 0 || goCount > 0:
 		// Single-sided detection
-		w.tinyGoCompiler = tinyCount > 0
-		w.wasmProject = true
-		//compiler := map[bool]string{true: "TinyGo", false: "Go"}[w.tinyGoCompiler]
-		//w.Logger("DEBUG: Detected WASM project, compiler:", compiler)
-	} else {
-		//w.Logger("DEBUG: No valid WASM signatures found in wasm_exec.js")
-		return false
+		return tinyCount > 0, true
+	default:
+		return false, false
 	}
+}
 
-	return true
+// findWasmExecJsInSearchPaths returns the first wasm_exec.js from Config.WasmExecJsSearchPaths
+// whose runtime signatures match the requested compiler. Entries may be directories
+// containing wasm_exec.js or direct paths to a .js file.
+func (w *TinyWasm) findWasmExecJsInSearchPaths(tinyGo bool) (string, bool) {
+	for _, entry := range w.Config.WasmExecJsSearchPaths {
+		candidate := entry
+		if filepath.Ext(entry) != ".js" {
+			candidate = filepath.Join(entry, "wasm_exec.js")
+		}
+
+		data, err := os.ReadFile(candidate)
+		if err != nil {
+			continue
+		}
+
+		if isTinyGo, ok := classifyWasmExecJs(string(data)); ok && isTinyGo == tinyGo {
+			return candidate, true
+		}
+	}
+	return "", false
 }
 
-// detectFromExistingWasmExecJs checks for existing wasm_exec.js file
-func (w *TinyWasm) detectFromExistingWasmExecJs() bool {
-	wasmExecPath := w.WasmExecJsOutputPath()
+// OnDiskWasmExecMode reports what the wasm_exec.js currently on disk was generated
+// for: the mode from its TinyWasm header ("" when it has no header, eg: copied by hand)
+// and the compiler runtime it contains ("go" or "tinygo", from its signatures). Compare
+// it with Value() to spot a stale file after a crash or an external edit.
+func (w *TinyWasm) OnDiskWasmExecMode() (mode string, compiler string, err error) {
+	outputPath := w.WasmExecJsOutputPath()
+	data, err := os.ReadFile(outputPath)
+	if err != nil {
+		return "", "", Err("reading wasm_exec.js:", err)
+	}
+	content := string(data)
 
-	// Check if file exists
-	if _, err := os.Stat(wasmExecPath); err != nil {
+	tinyGo, ok := classifyWasmExecJs(content)
+	if !ok {
+		return "", "", Errf("%s is not a recognized Go or TinyGo wasm_exec.js", outputPath)
+	}
+	compiler = "go"
+	if tinyGo {
+		compiler = "tinygo"
+	}
+
+	mode, _ = w.getModeFromWasmExecJsHeader(content)
+	return mode, compiler, nil
+}
+
+// detectModeFromWasmExecJsHeader restores the mode recorded in the header of an
+// existing wasm_exec.js, if any
+func (w *TinyWasm) detectModeFromWasmExecJsHeader() {
+	data, err := os.ReadFile(w.WasmExecJsOutputPath())
+	if err != nil {
+		return
+	}
+	if mode, found := w.getModeFromWasmExecJsHeader(string(data)); found {
+		w.modeMu.Lock()
+		w.currentMode = mode
+		w.modeMu.Unlock()
+	}
+}
+
+// detectFromWasmExecJsSignatures infers the compiler from the runtime signatures
+// of an existing wasm_exec.js and marks the project as WASM when found
+func (w *TinyWasm) detectFromWasmExecJsSignatures() bool {
+	data, err := os.ReadFile(w.WasmExecJsOutputPath())
+	if err != nil {
 		return false
 	}
 
-	// Analyze content to determine compiler type
-	return w.analyzeWasmExecJsContent(wasmExecPath)
+	tinyGo, ok := classifyWasmExecJs(string(data))
+	if !ok {
+		return false
+	}
+	w.tinyGoCompiler = tinyGo
+	w.wasmProject = true
+	return true
 }
@@ -4,12 +4,13 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"strings"
 	"testing"
 )
 
 // TestJavascriptHeaderRoundtrip ensures the generated wasm_exec.js contains a
-// TinyWasm header with the getSuccessMessage text and that analyzeWasmExecJsContent
-// can read it back and restore the mode.
+// TinyWasm header with the getSuccessMessage text and that the wasm_exec.js detection
+// steps read it back and restore the mode.
 func TestJavascriptHeaderRoundtrip(t *testing.T) {
 	if _, err := exec.LookPath("tinygo"); err != nil {
 		t.Skip("tinygo not found in PATH")
@@ -31,7 +32,10 @@ func TestJavascriptHeaderRoundtrip(t *testing.T) {
 		w.Config.BuildSmallSizeShortcut,
 	}
 
-	outPath := filepath.Join(tmpDir, "wasm_exec.js")
+	outPath := w.WasmExecJsOutputPath()
+	if err := os.MkdirAll(filepath.Dir(outPath), 0755); err != nil {
+		t.Fatal(err)
+	}
 
 	for _, mode := range shortcuts {
 		// Use a fresh TinyWasm instance per mode to avoid shared state
@@ -52,8 +56,9 @@ func TestJavascriptHeaderRoundtrip(t *testing.T) {
 		// Reset currentMode to ensure detection reads the header
 		w.currentMode = ""
 
-		if !w.analyzeWasmExecJsContent(outPath) {
-			t.Fatalf("analyzeWasmExecJsContent failed to detect header for mode %q", mode)
+		w.detectModeFromWasmExecJsHeader()
+		if !w.detectFromWasmExecJsSignatures() {
+			t.Fatalf("detectFromWasmExecJsSignatures failed to detect wasm_exec.js for mode %q", mode)
 		}
 
 		if w.Value() != mode {
@@ -61,3 +66,132 @@ func TestJavascriptHeaderRoundtrip(t *testing.T) {
 		}
 	}
 }
+
+// TestUpdateWasmExecJsHeaderKeepsBody ensures a same-runtime mode switch only
+// rewrites the header line and leaves the wasm_exec.js body untouched.
+func TestUpdateWasmExecJsHeaderKeepsBody(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	w := New(&Config{
+		AppRootDir: tmpDir,
+		Logger:     func(...any) {},
+	})
+	w.wasmProject = true
+	w.currentMode = w.Config.BuildMediumSizeShortcut
+
+	w.wasmProjectWriteOrReplaceWasmExecJsOutput()
+	original, err := os.ReadFile(w.WasmExecJsOutputPath())
+	if err != nil {
+		t.Fatalf("failed to read generated wasm_exec.js: %v", err)
+	}
+
+	if !w.sameWasmExecJsRuntime(w.Config.BuildMediumSizeShortcut, w.Config.BuildSmallSizeShortcut) {
+		t.Fatal("expected Medium and Small to share the TinyGo runtime")
+	}
+	if w.sameWasmExecJsRuntime(w.Config.BuildLargeSizeShortcut, w.Config.BuildSmallSizeShortcut) {
+		t.Fatal("expected Large and Small to use different runtimes")
+	}
+
+	if !w.updateWasmExecJsHeader(w.Config.BuildSmallSizeShortcut) {
+		t.Fatal("updateWasmExecJsHeader returned false for an existing file")
+	}
+
+	updated, err := os.ReadFile(w.WasmExecJsOutputPath())
+	if err != nil {
+		t.Fatalf("failed to read updated wasm_exec.js: %v", err)
+	}
+
+	if mode, ok := w.getModeFromWasmExecJsHeader(string(updated)); !ok || mode != w.Config.BuildSmallSizeShortcut {
+		t.Fatalf("expected header mode %q, got %q", w.Config.BuildSmallSizeShortcut, mode)
+	}
+
+	bodyOf := func(b []byte) string {
+		s := string(b)
+		return s[strings.Index(s, "\n")+1:]
+	}
+	if bodyOf(original) != bodyOf(updated) {
+		t.Fatal("wasm_exec.js body changed during header-only update")
+	}
+}
+
+// TestOnWasmExecJsWrittenFiresOnlyOnActualWrites verifies the callback fires for
+// real writes (full and header-only) and not when the content is unchanged.
+func TestOnWasmExecJsWrittenFiresOnlyOnActualWrites(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	type written struct{ path, mode string }
+	var calls []written
+
+	w := New(&Config{
+		AppRootDir: tmpDir,
+		Logger:     func(...any) {},
+		OnWasmExecJsWritten: func(path string, mode string) {
+			calls = append(calls, written{path, mode})
+		},
+	})
+	w.wasmProject = true
+
+	w.wasmProjectWriteOrReplaceWasmExecJsOutput()
+	if len(calls) != 1 {
+		t.Fatalf("expected 1 callback after first write, got %d", len(calls))
+	}
+	if calls[0].path != w.WasmExecJsOutputPath() || calls[0].mode != w.Config.BuildLargeSizeShortcut {
+		t.Errorf("unexpected callback arguments: %+v", calls[0])
+	}
+
+	// Same content: no write, no callback
+	w.wasmProjectWriteOrReplaceWasmExecJsOutput()
+	if len(calls) != 1 {
+		t.Fatalf("expected no callback for unchanged content, got %d calls", len(calls))
+	}
+
+	// Header-only update is an actual write
+	w.currentMode = w.Config.BuildMediumSizeShortcut
+	if !w.updateWasmExecJsHeader(w.Config.BuildMediumSizeShortcut) {
+		t.Fatal("updateWasmExecJsHeader failed")
+	}
+	if len(calls) != 2 || calls[1].mode != w.Config.BuildMediumSizeShortcut {
+		t.Fatalf("expected callback for header update with mode M, got %+v", calls)
+	}
+}
+
+func TestOnDiskWasmExecMode(t *testing.T) {
+	tmpDir := t.TempDir()
+	w := New(&Config{
+		AppRootDir:          tmpDir,
+		WasmExecJsOutputDir: "js",
+		Logger:              func(...any) {},
+	})
+
+	if _, _, err := w.OnDiskWasmExecMode(); err == nil {
+		t.Error("expected an error when wasm_exec.js does not exist")
+	}
+
+	jsPath := w.WasmExecJsOutputPath()
+	if err := os.MkdirAll(filepath.Dir(jsPath), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	write := func(content string) {
+		t.Helper()
+		if err := os.WriteFile(jsPath, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	write(wasmExecJsHeader("S") + strings.Join(wasm_execTinyGoSignatures(), "\n"))
+	if mode, compiler, err := w.OnDiskWasmExecMode(); err != nil || mode != "S" || compiler != "tinygo" {
+		t.Errorf("OnDiskWasmExecMode() = %q, %q, %v; want S, tinygo", mode, compiler, err)
+	}
+
+	// Hand-copied runtime without a TinyWasm header
+	write(strings.Join(wasm_execGoSignatures(), "\n"))
+	if mode, compiler, err := w.OnDiskWasmExecMode(); err != nil || mode != "" || compiler != "go" {
+		t.Errorf("OnDiskWasmExecMode() = %q, %q, %v; want no mode, go", mode, compiler, err)
+	}
+
+	write("console.log('not a runtime')")
+	if _, _, err := w.OnDiskWasmExecMode(); err == nil {
+		t.Error("expected an error for an unrecognized file")
+	}
+}
@@ -0,0 +1,69 @@
+package tinywasm
+
+import . "github.com/cdvelop/tinystring"
+
+// StandaloneLoaderJS returns a single self-contained script for quick demos: the
+// wasm_exec.js runtime followed by a loader that shows a loading indicator while
+// the module is fetched and instantiated, and renders any failure into the page.
+// Including this one file in a minimal HTML page is enough to run the app.
+func (h *TinyWasm) StandaloneLoaderJS() (string, error) {
+	if h.currentBuilder() == nil {
+		return "", Errf("activeBuilder not initialized")
+	}
+	return h.JavascriptForInitializing(wasmExecJsHeader(h.Value()), h.standaloneLoaderFooter())
+}
+
+// standaloneLoaderFooter returns the initialization code used by StandaloneLoaderJS
+func (h *TinyWasm) standaloneLoaderFooter() string {
+	wasmFile := h.wasmFileName()
+	goRef, declaration := h.goInstanceJs()
+
+	return `
+		(function () {
+			var doc = globalThis.document;
+			var indicator = null;
+
+			function mount(el) {
+				if (!doc) return;
+				if (doc.body) {
+					doc.body.appendChild(el);
+				} else {
+					doc.addEventListener("DOMContentLoaded", function () { doc.body.appendChild(el); });
+				}
+			}
+
+			function done() {
+				if (indicator && indicator.parentNode) indicator.parentNode.removeChild(indicator);
+			}
+
+			function fail(err) {
+				done();
+				console.error("tinywasm: failed to load ` + wasmFile + `", err);
+				if (!doc) return;
+				var box = doc.createElement("pre");
+				box.setAttribute("data-tinywasm", "error");
+				box.style.cssText = "margin:1em;padding:1em;border:1px solid #c00;background:#fee;color:#900;white-space:pre-wrap;font:14px monospace";
+				box.textContent = "Failed to load ` + wasmFile + `: " + (err && err.message ? err.message : String(err));
+				mount(box);
+			}
+
+			if (doc) {
+				indicator = doc.createElement("div");
+				indicator.setAttribute("data-tinywasm", "loading");
+				indicator.style.cssText = "margin:1em;font:14px sans-serif;color:#555";
+				indicator.textContent = "Loading...";
+				mount(indicator);
+			}
+
+			try {
+				` + declaration + `
+				` + h.instantiateJs(wasmFile, goRef+".importObject") + `.then(function (result) {
+					done();
+					return ` + goRef + `.run(result.instance);
+				}).catch(fail);
+			} catch (err) {
+				fail(err);
+			}
+		})();
+	`
+}
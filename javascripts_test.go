@@ -1,6 +1,9 @@
 package tinywasm
 
 import (
+	"errors"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 )
@@ -108,3 +111,235 @@ func TestJavascriptForInitializingSignatures(t *testing.T) {
 		t.Fatalf("expected TinyGo usage flag to change between debug and coding modes, but it did not")
 	}
 }
+
+// TestJavascriptFooterNamespace verifies Config.JSNamespace scopes the Go instance
+func TestJavascriptFooterNamespace(t *testing.T) {
+	w := New(&Config{AppRootDir: t.TempDir(), Logger: func(...any) {}})
+	w.wasmProject = true
+
+	js, err := w.JavascriptForInitializing()
+	if err != nil {
+		t.Fatalf("JavascriptForInitializing failed: %v", err)
+	}
+	if !strings.Contains(js, "const go = new Go();") || !strings.Contains(js, "go.run(result.instance);") {
+		t.Fatalf("default footer should use a global go instance")
+	}
+
+	w.Config.JSNamespace = "MyApp"
+	js, err = w.JavascriptForInitializing()
+	if err != nil {
+		t.Fatalf("JavascriptForInitializing failed: %v", err)
+	}
+
+	for _, want := range []string{
+		"globalThis.MyApp = globalThis.MyApp || {};",
+		"MyApp.go = new Go();",
+		"MyApp.go.importObject",
+		"MyApp.go.run(result.instance);",
+	} {
+		if !strings.Contains(js, want) {
+			t.Errorf("namespaced footer missing %q", want)
+		}
+	}
+	if strings.Contains(js, "const go = new Go();") {
+		t.Error("namespaced footer should not declare a global go instance")
+	}
+}
+
+// TestWasmExecJsSearchPaths verifies configured search paths win and are matched by compiler
+func TestWasmExecJsSearchPaths(t *testing.T) {
+	tmp := t.TempDir()
+	goDir := filepath.Join(tmp, "go-portable")
+	tinyDir := filepath.Join(tmp, "tinygo-portable")
+	for dir, content := range map[string]string{
+		goDir:   "// go runtime\nruntime.scheduleTimeoutEvent runtime.clearTimeoutEvent runtime.wasmExit\n",
+		tinyDir: "// tinygo runtime\nruntime.sleepTicks runtime.ticks tinygo_js\n",
+	} {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatalf("creating dir: %v", err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, "wasm_exec.js"), []byte(content), 0644); err != nil {
+			t.Fatalf("writing wasm_exec.js: %v", err)
+		}
+	}
+
+	w := New(&Config{
+		AppRootDir:              tmp,
+		DisableWasmExecJsOutput: true,
+		Logger:                  func(...any) {},
+		// The TinyGo dir is listed first: the Go lookup must skip it by signature
+		WasmExecJsSearchPaths: []string{filepath.Join(tmp, "missing"), tinyDir, goDir},
+	})
+
+	goPath, err := w.GetWasmExecJsPathGo()
+	if err != nil {
+		t.Fatalf("GetWasmExecJsPathGo failed: %v", err)
+	}
+	if want := filepath.Join(goDir, "wasm_exec.js"); goPath != want {
+		t.Errorf("GetWasmExecJsPathGo() = %s, want %s", goPath, want)
+	}
+
+	tinyPath, err := w.GetWasmExecJsPathTinyGo()
+	if err != nil {
+		t.Fatalf("GetWasmExecJsPathTinyGo failed: %v", err)
+	}
+	if want := filepath.Join(tinyDir, "wasm_exec.js"); tinyPath != want {
+		t.Errorf("GetWasmExecJsPathTinyGo() = %s, want %s", tinyPath, want)
+	}
+}
+
+// TestStandaloneLoaderJS verifies the single-file loader bundles runtime, loading and error UI
+func TestStandaloneLoaderJS(t *testing.T) {
+	w := New(&Config{AppRootDir: t.TempDir(), Logger: func(...any) {}})
+	w.wasmProject = true
+
+	js, err := w.StandaloneLoaderJS()
+	if err != nil {
+		t.Fatalf("StandaloneLoaderJS failed: %v", err)
+	}
+
+	for _, want := range []string{
+		wasmExecJsHeader(w.Value()),
+		"runtime.wasmExit", // wasm_exec.js runtime is included
+		`indicator.setAttribute("data-tinywasm", "loading");`,
+		`box.setAttribute("data-tinywasm", "error");`,
+		`fetch("main.wasm")`,
+		".catch(fail);",
+	} {
+		if !strings.Contains(js, want) {
+			t.Errorf("standalone loader missing %q", want)
+		}
+	}
+}
+
+// TestWriteWasmExecJs verifies wasm_exec.js can be regenerated on demand and failures are reported
+func TestWriteWasmExecJs(t *testing.T) {
+	tmp := t.TempDir()
+	writeWasmProject(t, tmp)
+
+	w := New(&Config{
+		AppRootDir:          tmp,
+		SourceDir:           "web",
+		OutputDir:           "web/public",
+		WasmExecJsOutputDir: "web/js",
+		MainInputFile:       "main.go",
+		Logger:              func(...any) {},
+	})
+
+	wasmExecJs := filepath.Join(tmp, "web", "js", "wasm_exec.js")
+	if err := os.Remove(wasmExecJs); err != nil {
+		t.Fatalf("wasm_exec.js should be written at New: %v", err)
+	}
+
+	if err := w.WriteWasmExecJs(); err != nil {
+		t.Fatalf("WriteWasmExecJs failed: %v", err)
+	}
+	data, err := os.ReadFile(wasmExecJs)
+	if err != nil || !strings.Contains(string(data), "main.wasm") {
+		t.Errorf("wasm_exec.js not regenerated: %v", err)
+	}
+
+	w.Config.DisableWasmExecJsOutput = true
+	if err := w.WriteWasmExecJs(); !errors.Is(err, ErrWasmExecJsOutputDisabled) {
+		t.Errorf("WriteWasmExecJs() = %v, want ErrWasmExecJsOutputDisabled", err)
+	}
+	w.Config.DisableWasmExecJsOutput = false
+
+	// A file in place of the output directory
+	w.Config.WasmExecJsOutputDir = "web/blocked"
+	if err := os.WriteFile(filepath.Join(tmp, w.Config.WasmExecJsOutputDir), nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.WriteWasmExecJs(); err == nil {
+		t.Error("expected write failure to be reported")
+	}
+}
+
+// TestCustomWasmExecPath verifies a project wasm_exec.js replaces the embedded runtime, with fallback
+func TestCustomWasmExecPath(t *testing.T) {
+	tmp := t.TempDir()
+	writeWasmProject(t, tmp)
+
+	var logs []string
+	w := New(&Config{
+		AppRootDir:              tmp,
+		SourceDir:               "web",
+		OutputDir:               "web/public",
+		MainInputFile:           "main.go",
+		CustomWasmExecGoPath:    "tools/wasm_exec.js",
+		DisableWasmExecJsOutput: true,
+		Logger: func(message ...any) {
+			for _, m := range message {
+				if s, ok := m.(string); ok {
+					logs = append(logs, s)
+				}
+			}
+		},
+	})
+
+	content, err := w.getWasmExecContent(w.Value())
+	if err != nil {
+		t.Fatalf("getWasmExecContent failed: %v", err)
+	}
+	if string(content) != string(embeddedWasmExecGo) {
+		t.Error("missing custom file should fall back to the embedded runtime")
+	}
+	if !strings.Contains(strings.Join(logs, "\n"), "custom wasm_exec.js not available") {
+		t.Error("fallback should log a warning")
+	}
+
+	custom := "// patched runtime\n" + string(embeddedWasmExecGo)
+	if err := os.MkdirAll(filepath.Join(tmp, "tools"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(tmp, "tools", "wasm_exec.js"), []byte(custom), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	js, err := w.JavascriptForInitializing()
+	if err != nil {
+		t.Fatalf("JavascriptForInitializing failed: %v", err)
+	}
+	if !strings.Contains(js, "// patched runtime") {
+		t.Error("custom wasm_exec.js should replace the embedded runtime")
+	}
+}
+
+// TestPreferToolchainWasmExec verifies the installed runtime is preferred and the embedded one is the fallback
+func TestPreferToolchainWasmExec(t *testing.T) {
+	tmp := t.TempDir()
+	writeWasmProject(t, tmp)
+
+	toolchainDir := filepath.Join(tmp, "goroot")
+	if err := os.MkdirAll(toolchainDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	toolchainJs := "// toolchain runtime\n" + string(embeddedWasmExecGo)
+	if err := os.WriteFile(filepath.Join(toolchainDir, "wasm_exec.js"), []byte(toolchainJs), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	w := New(&Config{
+		AppRootDir:              tmp,
+		SourceDir:               "web",
+		OutputDir:               "web/public",
+		MainInputFile:           "main.go",
+		PreferToolchainWasmExec: true,
+		WasmExecJsSearchPaths:   []string{toolchainDir},
+		DisableWasmExecJsOutput: true,
+		Logger:                  func(...any) {},
+	})
+
+	content, err := w.getWasmExecContent(w.Value())
+	if err != nil {
+		t.Fatalf("getWasmExecContent failed: %v", err)
+	}
+	if string(content) != toolchainJs {
+		t.Error("toolchain wasm_exec.js should be preferred")
+	}
+
+	w.Config.PreferToolchainWasmExec = false
+	if content, _ := w.getWasmExecContent(w.Value()); string(content) != string(embeddedWasmExecGo) {
+		t.Error("embedded wasm_exec.js should be used by default")
+	}
+}
@@ -0,0 +1,38 @@
+package tinywasm
+
+import (
+	"os"
+	"time"
+
+	. "github.com/cdvelop/tinystring"
+)
+
+// fast3GKbps is the downlink of the "Fast 3G" profile of browser devtools, used by
+// the wasm_get_size tool to frame the output size as a load time
+const fast3GKbps = 1600
+
+// EstimatedLoadTime returns how long downloading the current wasm takes at
+// connectionKbps (kilobits per second), eg: 1600 for a fast 3G connection. When a
+// precompressed copy (.br or .gz) sits next to the output the smallest one is used,
+// as that is what a correctly configured server sends. Latency and compile time
+// are not included: it is a lower bound meant to compare modes.
+func (w *TinyWasm) EstimatedLoadTime(connectionKbps int) (time.Duration, error) {
+	if connectionKbps <= 0 {
+		return 0, Errf("invalid connection speed %d kbps: must be positive", connectionKbps)
+	}
+
+	size := w.LastOutputSize()
+	if size == 0 {
+		return 0, Errf("no wasm output built yet for mode %s", w.Value())
+	}
+
+	output := w.outputPathFor(w.currentBuilder())
+	for _, compressed := range []string{output + ".br", output + ".gz"} {
+		if info, err := os.Stat(compressed); err == nil && info.Size() < size {
+			size = info.Size()
+		}
+	}
+
+	bits := size * 8
+	return time.Duration(bits) * time.Second / time.Duration(connectionKbps*1000), nil
+}
@@ -0,0 +1,47 @@
+package tinywasm
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestEstimatedLoadTime(t *testing.T) {
+	tmp := t.TempDir()
+	outputDir := filepath.Join(tmp, "public")
+	if err := os.MkdirAll(outputDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	w := New(&Config{
+		AppRootDir:              tmp,
+		OutputDir:               "public",
+		DisableWasmExecJsOutput: true,
+		Logger:                  func(...any) {},
+	})
+
+	if _, err := w.EstimatedLoadTime(fast3GKbps); err == nil {
+		t.Error("expected an error before any output exists")
+	}
+
+	// 200 KB at 1600 kbps: 1.6 Mbit / 1.6 Mbit/s = 1s
+	if err := os.WriteFile(filepath.Join(outputDir, "main.wasm"), make([]byte, 200_000), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := w.EstimatedLoadTime(1600); err != nil || got != time.Second {
+		t.Errorf("EstimatedLoadTime(1600) = %v, %v; want 1s", got, err)
+	}
+
+	// A precompressed copy is what gets served
+	if err := os.WriteFile(filepath.Join(outputDir, "main.wasm.gz"), make([]byte, 50_000), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := w.EstimatedLoadTime(1600); err != nil || got != 250*time.Millisecond {
+		t.Errorf("EstimatedLoadTime(1600) with gzip copy = %v, %v; want 250ms", got, err)
+	}
+
+	if _, err := w.EstimatedLoadTime(0); err == nil {
+		t.Error("expected an error for a zero connection speed")
+	}
+}
@@ -0,0 +1,66 @@
+package tinywasm
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/cdvelop/gobuild"
+)
+
+// mainInputPath returns the absolute path of the main input file
+func (w *TinyWasm) mainInputPath() string {
+	return filepath.Join(w.Config.AppRootDir, w.Config.SourceDir, w.Config.MainInputFile)
+}
+
+// isMainInputFile reports whether filePath is the configured main input file
+func (w *TinyWasm) isMainInputFile(filePath string) bool {
+	abs, err := filepath.Abs(filePath)
+	return err == nil && abs == w.mainInputPath()
+}
+
+// mainInputRemoved handles the removal (or the old path of a rename) of the main input:
+// running builds are cancelled and the project is no longer considered detected, so a
+// later write does not compile a path that is gone
+func (w *TinyWasm) mainInputRemoved() {
+	for _, b := range []*gobuild.GoBuild{w.builderLarge, w.builderMedium, w.builderSmall} {
+		b.Cancel()
+	}
+
+	w.wasmProject = false
+	w.detection = DetectionReport{Source: DetectionSourceNone, Compiler: w.compilerCommand(w.Value()), Mode: w.Value()}
+
+	w.buildMu.Lock()
+	w.fingerprints = nil
+	w.pendingFingerprints = nil
+	w.buildMu.Unlock()
+
+	w.Logger("Main input", w.mainInputPath(), "removed, waiting for it to reappear")
+}
+
+// relocateMainInput follows the main input renamed into another directory of AppRootDir:
+// SourceDir is updated to that directory and the builders are rebuilt for it, keeping the
+// active mode. Nothing changes while the configured main input still exists.
+func (w *TinyWasm) relocateMainInput(filePath string) {
+	if _, err := os.Stat(w.mainInputPath()); err == nil {
+		return
+	}
+	abs, err := filepath.Abs(filePath)
+	if err != nil {
+		return
+	}
+	sourceDir, err := filepath.Rel(w.Config.AppRootDir, filepath.Dir(abs))
+	if err != nil || sourceDir == ".." || strings.HasPrefix(sourceDir, ".."+string(filepath.Separator)) {
+		return // outside AppRootDir
+	}
+
+	w.Logger("Main input moved to", abs, "- SourceDir is now", sourceDir)
+	w.Config.SourceDir = sourceDir
+
+	mode := w.Value()
+	w.builderWasmInit()
+	w.modeMu.Lock()
+	w.activeBuilder = w.builderForMode(mode)
+	w.modeMu.Unlock()
+	w.wasmProject = false // detected again from the new location
+}
@@ -0,0 +1,80 @@
+package tinywasm
+
+import (
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/cdvelop/gobuild"
+)
+
+// ManagedFiles returns every path TinyWasm may create with the current configuration
+// (paths include AppRootDir): the wasm outputs, temp outputs, wasm_exec.js, the worker
+// script, symbols, the output manifest, the index.html of InitProject, editor
+// configuration and .gitignore. Temp outputs get unique names per build, so they are
+// listed as a glob pattern (eg: "public/main_temp*.wasm"); use filepath.Glob to expand
+// it. This single inventory is meant for cleanup, .gitignore generation and watcher-ignore
+// configuration. Source files such as the generated default main file are not included.
+func (w *TinyWasm) ManagedFiles() []string {
+	var files []string
+	add := func(p string) {
+		if !slices.Contains(files, p) {
+			files = append(files, p)
+		}
+	}
+
+	var temps []string
+	builders := []*gobuild.GoBuild{w.builderLarge, w.builderMedium, w.builderSmall}
+	for _, eb := range w.entries {
+		builders = append(builders, eb.builders[w.Config.BuildLargeSizeShortcut], eb.builders[w.Config.BuildMediumSizeShortcut], eb.builders[w.Config.BuildSmallSizeShortcut])
+	}
+	for _, b := range builders {
+		output := b.FinalOutputPath()
+		outputs := []string{output}
+		if w.Config.HashedOutputName && !w.isEntryBuilder(b) {
+			outputs = append(outputs, hashedOutputGlob(output))
+		}
+		for _, o := range outputs {
+			add(o)
+			if w.Config.EmitGzip {
+				add(o + gzipExtension)
+			}
+			if w.Config.EmitBrotli {
+				add(o + brotliExtension)
+			}
+			if w.Config.EmitSymbols {
+				add(o + symbolsFileExtension)
+			}
+		}
+		temp := strings.TrimSuffix(output, ".wasm") + "_temp*.wasm"
+		if !slices.Contains(temps, temp) {
+			temps = append(temps, temp)
+		}
+	}
+	for _, temp := range temps {
+		add(temp)
+	}
+	add(path.Join(w.Config.AppRootDir, w.Config.OutputDir, outputManifestName))
+	if w.Config.EmitBuildStats {
+		add(w.BuildStatsPath())
+	}
+
+	if !w.Config.DisableWasmExecJsOutput {
+		add(w.WasmExecJsOutputPath())
+		if w.Config.GenerateWorker {
+			add(w.WorkerOutputPath())
+		}
+	}
+
+	if !w.skipHTMLGeneration() {
+		add(w.indexHTMLPath())
+	}
+
+	add(path.Join(w.Config.AppRootDir, ".vscode", "settings.json"))
+	if w.Config.GoLandConfig {
+		add(path.Join(w.Config.AppRootDir, ".idea", "workspace.xml"))
+	}
+	add(w.gitignorePath())
+
+	return files
+}
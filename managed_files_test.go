@@ -0,0 +1,38 @@
+package tinywasm
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestManagedFiles(t *testing.T) {
+	w := New(&Config{
+		AppRootDir:          "/project",
+		OutputDir:           "web/public",
+		WasmExecJsOutputDir: "web/js",
+		Logger:              func(...any) {},
+	})
+
+	expected := []string{
+		"/project/web/public/main.wasm",
+		"/project/web/public/main_temp*.wasm",
+		"/project/web/public/.tinywasm-outputs",
+		"/project/web/js/wasm_exec.js",
+		"/project/web/public/index.html",
+		"/project/.vscode/settings.json",
+		"/project/.gitignore",
+	}
+	if got := w.ManagedFiles(); !slices.Equal(got, expected) {
+		t.Errorf("ManagedFiles() = %v, want %v", got, expected)
+	}
+
+	w.Config.EmitSymbols = true
+	w.Config.DisableWasmExecJsOutput = true
+	got := w.ManagedFiles()
+	if !slices.Contains(got, "/project/web/public/main.wasm.symbols") {
+		t.Errorf("ManagedFiles() = %v, missing symbols file", got)
+	}
+	if slices.Contains(got, "/project/web/js/wasm_exec.js") {
+		t.Errorf("ManagedFiles() = %v, should not list wasm_exec.js when its output is disabled", got)
+	}
+}
@@ -1,5 +1,12 @@
 package tinywasm
 
+import (
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
 // ToolExecutor defines how a tool should be executed
 // Channel accepts string messages (no binary data in tinywasm)
 type ToolExecutor func(args map[string]any, progress chan<- any)
@@ -77,9 +84,75 @@ func (w *TinyWasm) GetMCPToolsMetadata() []ToolMetadata {
 			Description: "Get current WASM file size and comparison across all three modes (LARGE/MEDIUM/SMALL) to help decide optimal size/feature tradeoff for production.",
 			Parameters:  []ParameterMetadata{},
 			Execute: func(args map[string]any, progress chan<- any) {
-				// TODO: Implement size retrieval from TinyWasm
-				progress <- "Current WASM size: [not implemented yet]"
+				progress <- w.modeSizesSummary()
+
+				size := w.LastOutputSize()
+				if size == 0 {
+					progress <- "Current WASM size: no output built yet for mode " + w.Value()
+					return
+				}
+				progress <- "Current WASM size (mode " + w.Value() + "): " + strconv.FormatInt(size, 10) + " bytes"
+				if loadTime, err := w.EstimatedLoadTime(fast3GKbps); err == nil {
+					progress <- "Estimated load time on 3G (" + strconv.Itoa(fast3GKbps) + " kbps): ~" + loadTime.Round(100*time.Millisecond).String()
+				}
 			},
 		},
 	}
 }
+
+// modeSizesSummary returns the wasm size of every mode, eg:
+// "LARGE: 2.1MB, MEDIUM: 480KB, SMALL: 190KB". The active mode is read with
+// GetWasmSize. Other modes are read from their own output file when
+// it differs (see DebugOutputSuffix), otherwise from their last successful build of
+// this session; "not compiled" when neither is available.
+func (w *TinyWasm) modeSizesSummary() string {
+	modes := []struct{ label, mode string }{
+		{"LARGE", w.Config.BuildLargeSizeShortcut},
+		{"MEDIUM", w.Config.BuildMediumSizeShortcut},
+		{"SMALL", w.Config.BuildSmallSizeShortcut},
+	}
+
+	history := w.BuildHistory(0)
+
+	parts := make([]string, 0, len(modes))
+	for _, m := range modes {
+		size := int64(-1)
+
+		output := w.outputPathFor(w.builderForMode(m.mode))
+		if m.mode == w.Value() {
+			if current, err := w.GetWasmSize(); err == nil {
+				size = current
+			}
+		} else if w.builderForMode(m.mode).FinalOutputPath() != w.currentBuilder().FinalOutputPath() {
+			if info, err := os.Stat(output); err == nil {
+				size = info.Size()
+			}
+		} else {
+			for _, result := range history {
+				if result.Mode == m.mode && result.Err == nil {
+					size = result.Size
+					break
+				}
+			}
+		}
+
+		if size < 0 {
+			parts = append(parts, m.label+": not compiled")
+		} else {
+			parts = append(parts, m.label+": "+formatSize(size))
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// formatSize returns a short human readable size, eg: "190KB", "2.1MB"
+func formatSize(bytes int64) string {
+	switch {
+	case bytes >= 1024*1024:
+		return strconv.FormatFloat(float64(bytes)/(1024*1024), 'f', 1, 64) + "MB"
+	case bytes >= 1024:
+		return strconv.FormatInt((bytes+512)/1024, 10) + "KB"
+	default:
+		return strconv.FormatInt(bytes, 10) + "B"
+	}
+}
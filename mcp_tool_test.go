@@ -0,0 +1,53 @@
+package tinywasm
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestWasmGetSizeReportsAllModes(t *testing.T) {
+	tmp := t.TempDir()
+	outputDir := filepath.Join(tmp, "public")
+	if err := os.MkdirAll(outputDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	w := New(&Config{
+		AppRootDir:              tmp,
+		OutputDir:               "public",
+		DebugOutputSuffix:       ".debug",
+		DisableWasmExecJsOutput: true,
+		Logger:                  func(...any) {},
+	})
+
+	if got := w.modeSizesSummary(); got != "LARGE: not compiled, MEDIUM: not compiled, SMALL: not compiled" {
+		t.Errorf("summary before any build = %q", got)
+	}
+
+	writeSized := func(name string, size int) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(outputDir, name), make([]byte, size), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	writeSized("main.wasm", 2_200_000)      // Large, active
+	writeSized("main.debug.wasm", 480*1024) // Medium has its own output
+	w.recordOutputSize("S", 190*1024)       // Small shares main.wasm: only known from history
+	w.buildStarted(w.Config.BuildSmallSizeShortcut)
+	w.buildFinished(w.Config.BuildSmallSizeShortcut, nil)
+
+	if got := w.modeSizesSummary(); got != "LARGE: 2.1MB, MEDIUM: 480KB, SMALL: 190KB" {
+		t.Errorf("summary = %q", got)
+	}
+
+	tool := w.GetMCPToolsMetadata()[1]
+	progress := make(chan any, 10)
+	tool.Execute(nil, progress)
+	close(progress)
+	first := <-progress
+	if msg, _ := first.(string); !strings.HasPrefix(msg, "LARGE: ") {
+		t.Errorf("wasm_get_size should start with the per-mode summary, got %v", first)
+	}
+}
@@ -2,6 +2,7 @@ package tinywasm
 
 import (
 	"path/filepath"
+	"strings"
 	"testing"
 )
 
@@ -151,3 +152,65 @@ func TestOutputRelativePathConsistency(t *testing.T) {
 			resultCoding, resultDebug, resultProd)
 	}
 }
+
+// TestDebugOutputSuffix verifies the Medium build output can coexist with the production output
+func TestDebugOutputSuffix(t *testing.T) {
+	w := New(&Config{
+		AppRootDir:              "/project",
+		OutputDir:               "web/public",
+		DebugOutputSuffix:       ".debug",
+		DisableWasmExecJsOutput: true,
+		Logger:                  func(...any) {},
+	})
+
+	if got := w.OutputRelativePath(); got != "web/public/main.wasm" {
+		t.Errorf("Large OutputRelativePath() = %s, want web/public/main.wasm", got)
+	}
+
+	w.updateCurrentBuilder(w.Config.BuildMediumSizeShortcut)
+	if got := w.OutputRelativePath(); got != "web/public/main.debug.wasm" {
+		t.Errorf("Medium OutputRelativePath() = %s, want web/public/main.debug.wasm", got)
+	}
+	if got := w.MainOutputFileAbsolutePath(); got != "/project/web/public/main.debug.wasm" {
+		t.Errorf("Medium MainOutputFileAbsolutePath() = %s", got)
+	}
+	if footer := w.defaultJsFooter(); !strings.Contains(footer, `fetch("main.debug.wasm")`) {
+		t.Errorf("Medium footer should fetch main.debug.wasm:\n%s", footer)
+	}
+	if !w.isTempOutputName("main.debug_temp_123.wasm") {
+		t.Error("debug temp outputs should be recognized")
+	}
+
+	w.updateCurrentBuilder(w.Config.BuildSmallSizeShortcut)
+	if got := w.OutputRelativePath(); got != "web/public/main.wasm" {
+		t.Errorf("Small OutputRelativePath() = %s, want web/public/main.wasm", got)
+	}
+}
+
+// TestOutputNamePerMode verifies per-mode output names and the OutputName fallback
+func TestOutputNamePerMode(t *testing.T) {
+	w := New(&Config{
+		AppRootDir:              "/project",
+		OutputDir:               "web/public",
+		OutputNamePerMode:       map[string]string{"L": "app-dev", "S": "app"},
+		DisableWasmExecJsOutput: true,
+		Logger:                  func(...any) {},
+	})
+
+	if got := w.OutputRelativePath(); got != "web/public/app-dev.wasm" {
+		t.Errorf("Large OutputRelativePath() = %s, want web/public/app-dev.wasm", got)
+	}
+
+	w.updateCurrentBuilder(w.Config.BuildMediumSizeShortcut)
+	if got := w.OutputRelativePath(); got != "web/public/main.wasm" {
+		t.Errorf("Medium OutputRelativePath() = %s, want OutputName fallback web/public/main.wasm", got)
+	}
+
+	w.updateCurrentBuilder(w.Config.BuildSmallSizeShortcut)
+	if got := w.OutputRelativePath(); got != "web/public/app.wasm" {
+		t.Errorf("Small OutputRelativePath() = %s, want web/public/app.wasm", got)
+	}
+	if footer := w.defaultJsFooter(); !strings.Contains(footer, `fetch("app.wasm")`) {
+		t.Errorf("Small footer should fetch app.wasm:\n%s", footer)
+	}
+}
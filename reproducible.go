@@ -0,0 +1,193 @@
+package tinywasm
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	. "github.com/cdvelop/tinystring"
+)
+
+// BuildDigest returns a stable SHA-256 digest identifying the current build of the active mode.
+// It is computed from:
+//   - the resolved compiler command and its compiling arguments
+//   - the toolchain version (go or tinygo)
+//   - the hashes of the Go sources under SourceDir plus go.mod/go.sum in AppRootDir
+//   - the hash of the wasm output file
+//
+// Paths are hashed relative to AppRootDir, so the project location does not affect the digest.
+// Two machines produce the same digest only when the build itself is deterministic:
+// the same toolchain version, the same module graph and flags that strip machine-specific
+// data from the binary (e.g. "-trimpath" for the Go compiler via CompilingArguments).
+// Comparing the digest in CI then detects non-reproducible builds.
+func (w *TinyWasm) BuildDigest() (string, error) {
+	mode := w.Value()
+	command := w.compilerCommand(mode)
+
+	version, err := toolchainVersion(command)
+	if err != nil {
+		return "", err
+	}
+
+	outputHash, err := fileSHA256(w.outputPathFor(w.builderForMode(mode)))
+	if err != nil {
+		return "", Err("wasm output not available, build first:", err)
+	}
+
+	sources, err := w.sourceFileHashes()
+	if err != nil {
+		return "", err
+	}
+
+	h := sha256.New()
+	io.WriteString(h, "command "+command+"\n")
+	io.WriteString(h, "args "+strings.Join(w.compilingArguments(mode), " ")+"\n")
+	io.WriteString(h, "toolchain "+version+"\n")
+	for _, s := range sources {
+		io.WriteString(h, "source "+s+"\n")
+	}
+	io.WriteString(h, "output "+outputHash+"\n")
+
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// AssertReproducibleEnv returns the conditions that would make a build of the active
+// mode non-reproducible, as human readable warnings (empty when none were found):
+//   - no compiler installed
+//   - environment variables that silently change the build (GOFLAGS, GOEXPERIMENT, GOTOOLCHAIN)
+//   - a toolchain not pinned by a "toolchain" directive in go.mod
+//   - Go mode without "-trimpath" (absolute paths are embedded) or without
+//     "-buildvcs=false" (VCS state, including a dirty worktree, is stamped)
+//
+// It is advisory and does not build anything: use it as a release-readiness gate
+// before comparing BuildDigest values across machines.
+func (w *TinyWasm) AssertReproducibleEnv() []string {
+	var warnings []string
+
+	if w.noToolchain {
+		warnings = append(warnings, "no toolchain installed: "+noToolchainMessage)
+	}
+
+	for _, name := range []string{"GOFLAGS", "GOEXPERIMENT", "GOTOOLCHAIN"} {
+		if value := os.Getenv(name); value != "" {
+			warnings = append(warnings, name+"="+value+" is set in the environment and changes the build")
+		}
+	}
+
+	goMod, err := os.ReadFile(filepath.Join(w.Config.AppRootDir, "go.mod"))
+	if err != nil {
+		warnings = append(warnings, "go.mod not found in "+w.Config.AppRootDir+": module graph and toolchain are not pinned")
+	} else if !hasToolchainDirective(string(goMod)) {
+		warnings = append(warnings, "toolchain not pinned: add a \"toolchain\" directive to go.mod")
+	}
+
+	mode := w.Value()
+	if w.compilerCommand(mode) == "go" {
+		args := strings.Join(w.compilingArguments(mode), " ")
+		if !strings.Contains(args, "-trimpath") {
+			warnings = append(warnings, "-trimpath missing: absolute source paths are embedded in the wasm")
+		}
+		if !strings.Contains(args, "-buildvcs=false") {
+			warnings = append(warnings, "-buildvcs=false missing: VCS state (dirty worktree, revision) is stamped into the wasm")
+		}
+	}
+
+	return warnings
+}
+
+// hasToolchainDirective reports whether go.mod content contains a toolchain directive
+func hasToolchainDirective(goMod string) bool {
+	for _, line := range strings.Split(goMod, "\n") {
+		if fields := strings.Fields(line); len(fields) == 2 && fields[0] == "toolchain" {
+			return true
+		}
+	}
+	return false
+}
+
+// sourceFileHashes returns sorted "relative/path hash" entries for the .go files
+// under SourceDir (tests excluded) and the go.mod/go.sum files in AppRootDir
+func (w *TinyWasm) sourceFileHashes() ([]string, error) {
+	var files []string
+
+	sourceDir := filepath.Join(w.Config.AppRootDir, w.Config.SourceDir)
+	err := filepath.Walk(sourceDir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() || filepath.Ext(path) != ".go" || HasSuffix(path, "_test.go") {
+			return nil
+		}
+		files = append(files, path)
+		return nil
+	})
+	if err != nil {
+		return nil, Err("walking source directory:", err)
+	}
+
+	for _, name := range []string{"go.mod", "go.sum"} {
+		modFile := filepath.Join(w.Config.AppRootDir, name)
+		if _, err := os.Stat(modFile); err == nil {
+			files = append(files, modFile)
+		}
+	}
+
+	entries := make([]string, 0, len(files))
+	for _, file := range files {
+		hash, err := fileSHA256(file)
+		if err != nil {
+			return nil, err
+		}
+		rel, err := filepath.Rel(w.Config.AppRootDir, file)
+		if err != nil {
+			rel = file
+		}
+		entries = append(entries, filepath.ToSlash(rel)+" "+hash)
+	}
+	sort.Strings(entries)
+
+	return entries, nil
+}
+
+// fileSHA256 returns the hex encoded SHA-256 of a file's content
+func fileSHA256(filePath string) (string, error) {
+	f, err := os.Open(filePath)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// toolchainVersion returns the host-independent version of the given compiler
+// eg: "go1.25.2" for go, "0.39.0" for tinygo
+func toolchainVersion(command string) (string, error) {
+	if command == "go" {
+		out, err := exec.Command("go", "env", "GOVERSION").Output()
+		if err != nil {
+			return "", Err("failed to get Go version:", err)
+		}
+		return strings.TrimSpace(string(out)), nil
+	}
+
+	// "tinygo version 0.39.0 linux/amd64 (using go version go1.25.2 and LLVM version 20.1.1)"
+	out, err := exec.Command(command, "version").Output()
+	if err != nil {
+		return "", Err("failed to get", command, "version:", err)
+	}
+	fields := strings.Fields(string(out))
+	if len(fields) < 3 {
+		return "", Err("unexpected", command, "version output:", string(out))
+	}
+	return fields[2], nil
+}
@@ -0,0 +1,107 @@
+package tinywasm
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writeDigestProject creates a minimal project with a fake wasm output in dir
+func writeDigestProject(t *testing.T, dir, source string) *TinyWasm {
+	t.Helper()
+	files := map[string]string{
+		"go.mod":                 "module test\n\ngo 1.21\n",
+		"web/main.go":            source,
+		"web/public/main.wasm":   "\x00asm\x01\x00\x00\x00",
+		"web/helpers_test.go":    "package main\n",
+		"web/components/card.go": "package components\n",
+	}
+	for name, content := range files {
+		p := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+			t.Fatalf("creating dir: %v", err)
+		}
+		if err := os.WriteFile(p, []byte(content), 0644); err != nil {
+			t.Fatalf("writing %s: %v", name, err)
+		}
+	}
+
+	return New(&Config{
+		AppRootDir:              dir,
+		SourceDir:               "web",
+		OutputDir:               "web/public",
+		DisableWasmExecJsOutput: true,
+		Logger:                  func(...any) {},
+	})
+}
+
+func TestBuildDigestIsStableAcrossLocations(t *testing.T) {
+	source := "package main\n\nfunc main() {}\n"
+
+	first, err := writeDigestProject(t, t.TempDir(), source).BuildDigest()
+	if err != nil {
+		t.Fatalf("BuildDigest failed: %v", err)
+	}
+
+	second, err := writeDigestProject(t, t.TempDir(), source).BuildDigest()
+	if err != nil {
+		t.Fatalf("BuildDigest failed: %v", err)
+	}
+
+	if first != second {
+		t.Errorf("expected identical digests for identical inputs, got %s and %s", first, second)
+	}
+
+	changed, err := writeDigestProject(t, t.TempDir(), "package main\n\nfunc main() { println(1) }\n").BuildDigest()
+	if err != nil {
+		t.Fatalf("BuildDigest failed: %v", err)
+	}
+
+	if changed == first {
+		t.Error("expected digest to change when a source file changes")
+	}
+}
+
+func TestBuildDigestRequiresOutput(t *testing.T) {
+	tmp := t.TempDir()
+	w := writeDigestProject(t, tmp, "package main\n\nfunc main() {}\n")
+	os.Remove(filepath.Join(tmp, "web", "public", "main.wasm"))
+
+	if _, err := w.BuildDigest(); err == nil {
+		t.Error("expected error when the wasm output does not exist")
+	}
+}
+
+func TestAssertReproducibleEnv(t *testing.T) {
+	for _, name := range []string{"GOFLAGS", "GOEXPERIMENT", "GOTOOLCHAIN"} {
+		t.Setenv(name, "")
+	}
+
+	tmp := t.TempDir()
+	w := writeDigestProject(t, tmp, "package main\n\nfunc main() {}\n")
+	w.noToolchain = false
+
+	warnings := strings.Join(w.AssertReproducibleEnv(), "\n")
+	for _, expected := range []string{"toolchain not pinned", "-trimpath", "-buildvcs=false"} {
+		if !strings.Contains(warnings, expected) {
+			t.Errorf("expected a warning about %q, got:\n%s", expected, warnings)
+		}
+	}
+
+	if err := os.WriteFile(filepath.Join(tmp, "go.mod"), []byte("module test\n\ngo 1.21\n\ntoolchain go1.25.2\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	w.Config.CompilingArguments = func() []string { return []string{"-trimpath", "-buildvcs=false"} }
+	t.Setenv("GOFLAGS", "-mod=mod")
+
+	warnings = strings.Join(w.AssertReproducibleEnv(), "\n")
+	if !strings.Contains(warnings, "GOFLAGS=-mod=mod") {
+		t.Errorf("expected a GOFLAGS warning, got:\n%s", warnings)
+	}
+
+	t.Setenv("GOFLAGS", "")
+	if remaining := w.AssertReproducibleEnv(); len(remaining) != 0 {
+		t.Errorf("expected no warnings for a pinned, clean setup, got %v", remaining)
+	}
+}
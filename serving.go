@@ -0,0 +1,45 @@
+package tinywasm
+
+import (
+	"mime"
+	"path/filepath"
+	"strings"
+)
+
+// ArtifactServingInfo returns the Content-Type and Content-Encoding to serve a
+// managed artifact with, from its file name: "main.wasm.br" is application/wasm with
+// "br" encoding, "wasm_exec.js" is text/javascript with "identity". Every server
+// integration should use it so the wasm MIME type (required by instantiateStreaming)
+// is never left to the platform's defaults.
+func (w *TinyWasm) ArtifactServingInfo(path string) (mimeType, contentEncoding string) {
+	name := filepath.Base(path)
+
+	contentEncoding = "identity"
+	switch {
+	case strings.HasSuffix(name, gzipExtension):
+		contentEncoding = "gzip"
+		name = strings.TrimSuffix(name, gzipExtension)
+	case strings.HasSuffix(name, brotliExtension):
+		contentEncoding = "br"
+		name = strings.TrimSuffix(name, brotliExtension)
+	}
+
+	switch ext := strings.ToLower(filepath.Ext(name)); ext {
+	case ".wasm":
+		mimeType = "application/wasm"
+	case ".js", ".mjs":
+		mimeType = "text/javascript; charset=utf-8"
+	case ".html":
+		mimeType = "text/html; charset=utf-8"
+	case ".json", ".map":
+		mimeType = "application/json"
+	case symbolsFileExtension:
+		mimeType = "text/plain; charset=utf-8"
+	default:
+		mimeType = mime.TypeByExtension(ext)
+		if mimeType == "" {
+			mimeType = "application/octet-stream"
+		}
+	}
+	return mimeType, contentEncoding
+}
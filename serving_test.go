@@ -0,0 +1,26 @@
+package tinywasm
+
+import "testing"
+
+func TestArtifactServingInfo(t *testing.T) {
+	w := New(&Config{AppRootDir: t.TempDir(), Logger: func(...any) {}})
+
+	tests := []struct {
+		path, mimeType, encoding string
+	}{
+		{"public/main.wasm", "application/wasm", "identity"},
+		{"public/main.wasm.gz", "application/wasm", "gzip"},
+		{"public/main.debug.wasm.br", "application/wasm", "br"},
+		{"js/wasm_exec.js", "text/javascript; charset=utf-8", "identity"},
+		{"js/wasm_worker.js.gz", "text/javascript; charset=utf-8", "gzip"},
+		{"public/index.html", "text/html; charset=utf-8", "identity"},
+		{"public/main.wasm.symbols", "text/plain; charset=utf-8", "identity"},
+		{"public/blob", "application/octet-stream", "identity"},
+	}
+	for _, tt := range tests {
+		mimeType, encoding := w.ArtifactServingInfo(tt.path)
+		if mimeType != tt.mimeType || encoding != tt.encoding {
+			t.Errorf("ArtifactServingInfo(%q) = %q, %q; want %q, %q", tt.path, mimeType, encoding, tt.mimeType, tt.encoding)
+		}
+	}
+}
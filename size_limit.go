@@ -0,0 +1,25 @@
+package tinywasm
+
+import (
+	"slices"
+
+	. "github.com/cdvelop/tinystring"
+)
+
+// checkMaxWasmBytes returns an error when the wasm output of mode is over
+// Config.MaxWasmBytes and the limit applies to mode (see Config.MaxWasmBytesModes)
+func (w *TinyWasm) checkMaxWasmBytes(mode string, size int64) error {
+	if w.Config.MaxWasmBytes <= 0 || size <= w.Config.MaxWasmBytes {
+		return nil
+	}
+
+	modes := w.Config.MaxWasmBytesModes
+	if len(modes) == 0 {
+		modes = []string{w.Config.BuildSmallSizeShortcut}
+	}
+	if !slices.ContainsFunc(modes, func(m string) bool { return Convert(m).ToUpper().String() == mode }) {
+		return nil
+	}
+
+	return Err("wasm size", formatSize(size), "exceeds limit", formatSize(w.Config.MaxWasmBytes), "in mode", mode)
+}
@@ -0,0 +1,43 @@
+package tinywasm
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestMaxWasmBytes(t *testing.T) {
+	w := New(&Config{AppRootDir: t.TempDir(), MaxWasmBytes: 256 * 1024, Logger: func(...any) {}})
+
+	err := w.checkMaxWasmBytes(w.Config.BuildSmallSizeShortcut, 340*1024)
+	if err == nil || !strings.Contains(err.Error(), "wasm size 340KB exceeds limit 256KB") {
+		t.Errorf("Small over the limit: err = %v", err)
+	}
+	if err := w.checkMaxWasmBytes(w.Config.BuildLargeSizeShortcut, 340*1024); err != nil {
+		t.Errorf("the limit applies to Small only by default, got %v", err)
+	}
+	if err := w.checkMaxWasmBytes(w.Config.BuildSmallSizeShortcut, 200*1024); err != nil {
+		t.Errorf("Small under the limit: err = %v", err)
+	}
+}
+
+// TestMaxWasmBytesFailsBuild verifies an oversized build is reported by NewFileEvent
+func TestMaxWasmBytesFailsBuild(t *testing.T) {
+	tmp := t.TempDir()
+	writeWasmProject(t, tmp)
+
+	w := New(&Config{
+		AppRootDir:              tmp,
+		SourceDir:               "web",
+		OutputDir:               "web/public",
+		DisableWasmExecJsOutput: true,
+		MaxWasmBytes:            1024,
+		MaxWasmBytesModes:       []string{"l"},
+		Logger:                  func(...any) {},
+	})
+
+	err := w.NewFileEvent("main.go", ".go", filepath.Join(tmp, "web", "main.go"), "write")
+	if err == nil || !strings.Contains(err.Error(), "exceeds limit 1KB") {
+		t.Errorf("NewFileEvent() = %v, want the size limit error", err)
+	}
+}
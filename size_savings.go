@@ -0,0 +1,49 @@
+package tinywasm
+
+import (
+	"strconv"
+	"strings"
+)
+
+// SizeSavingsReport compares the TinyGo modes against the Go Large baseline, eg:
+// "TinyGo SMALL is 91% smaller than Go LARGE (2.1MB → 190KB)", one line per TinyGo mode.
+// Sizes come from CompareSizes, so missing or stale per-mode artifacts are built first.
+// Without TinyGo installed the report says so instead of comparing.
+func (w *TinyWasm) SizeSavingsReport() (string, error) {
+	sizes, err := w.CompareSizes()
+	if err != nil {
+		return "", err
+	}
+	return w.sizeSavings(sizes), nil
+}
+
+// sizeSavings formats the SizeSavingsReport of the per-mode sizes
+func (w *TinyWasm) sizeSavings(sizes map[string]int64) string {
+	baseline := sizes[w.Config.BuildLargeSizeShortcut]
+	if baseline <= 0 {
+		return "Go LARGE not compiled, nothing to compare"
+	}
+
+	var lines []string
+	for _, m := range []struct{ label, mode string }{
+		{"MEDIUM", w.Config.BuildMediumSizeShortcut},
+		{"SMALL", w.Config.BuildSmallSizeShortcut},
+	} {
+		size := sizes[m.mode]
+		if size <= 0 {
+			continue // skipped without TinyGo
+		}
+
+		delta := (baseline - size) * 100 / baseline
+		comparison := strconv.FormatInt(delta, 10) + "% smaller"
+		if delta < 0 {
+			comparison = strconv.FormatInt(-delta, 10) + "% larger"
+		}
+		lines = append(lines, "TinyGo "+m.label+" is "+comparison+" than Go LARGE ("+formatSize(baseline)+" → "+formatSize(size)+")")
+	}
+
+	if len(lines) == 0 {
+		return "TinyGo is not installed, only Go LARGE was compiled (" + formatSize(baseline) + "): install TinyGo to compare"
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,21 @@
+package tinywasm
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSizeSavings(t *testing.T) {
+	w := New(&Config{AppRootDir: t.TempDir(), Logger: func(...any) {}})
+
+	report := w.sizeSavings(map[string]int64{"L": 2202010, "M": 491520, "S": 194560})
+	want := "TinyGo MEDIUM is 77% smaller than Go LARGE (2.1MB → 480KB)\n" +
+		"TinyGo SMALL is 91% smaller than Go LARGE (2.1MB → 190KB)"
+	if report != want {
+		t.Errorf("sizeSavings() =\n%s\nwant\n%s", report, want)
+	}
+
+	if report := w.sizeSavings(map[string]int64{"L": 2202010, "M": 0, "S": 0}); !strings.Contains(report, "TinyGo is not installed") {
+		t.Errorf("sizeSavings() without TinyGo = %q", report)
+	}
+}
@@ -0,0 +1,148 @@
+package tinywasm
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	. "github.com/cdvelop/tinystring"
+)
+
+// outputManifestName is the file in OutputDir listing every OutputName that has been
+// built there and not cleaned yet, one per line
+const outputManifestName = ".tinywasm-outputs"
+
+// StaleOutputs returns the existing files in OutputDir produced under a previous
+// OutputName (wasm, compressed copies, symbols and temp outputs), eg: main.wasm and
+// main.wasm.gz after renaming OutputName from "main" to "app". Previous names are
+// taken from the output manifest recorded by successful builds, so only files
+// TinyWasm itself produced are reported.
+func (w *TinyWasm) StaleOutputs() []string {
+	var stale []string
+	current := w.currentOutputNames()
+	for _, name := range w.outputManifestNames() {
+		if slices.Contains(current, name) {
+			continue
+		}
+		for _, file := range w.outputFilesForName(name) {
+			if !slices.Contains(stale, file) {
+				stale = append(stale, file)
+			}
+		}
+	}
+	return stale
+}
+
+// CleanStaleOutputs removes the files reported by StaleOutputs and resets the output
+// manifest to the current output names. It is never called automatically.
+func (w *TinyWasm) CleanStaleOutputs() error {
+	for _, file := range w.StaleOutputs() {
+		if err := os.Remove(file); err != nil && !os.IsNotExist(err) {
+			return Err("removing stale output:", err)
+		}
+		w.Logger("Removed stale output:", file)
+	}
+
+	manifest := w.outputManifestPath()
+	if _, err := os.Stat(manifest); err != nil {
+		return nil
+	}
+	content := strings.Join(w.currentOutputNames(), "\n") + "\n"
+	if err := os.WriteFile(manifest, []byte(content), w.outputFilePerm()); err != nil {
+		return Err("updating output manifest:", err)
+	}
+	return nil
+}
+
+// outputFilesForName returns the existing output files for an OutputName, including
+// the DebugOutputSuffix and hashed (Config.HashedOutputName) variants
+func (w *TinyWasm) outputFilesForName(name string) []string {
+	outputDir := filepath.Join(w.Config.AppRootDir, w.Config.OutputDir)
+
+	bases := []string{name}
+	if w.Config.DebugOutputSuffix != "" {
+		bases = append(bases, name+w.Config.DebugOutputSuffix)
+	}
+
+	var files []string
+	for _, base := range bases {
+		wasm := filepath.Join(outputDir, base+".wasm")
+		for _, file := range []string{wasm, wasm + gzipExtension, wasm + brotliExtension, wasm + symbolsFileExtension} {
+			if _, err := os.Stat(file); err == nil {
+				files = append(files, file)
+			}
+		}
+		if temps, err := filepath.Glob(filepath.Join(outputDir, base+"_temp*.wasm")); err == nil {
+			files = append(files, temps...)
+		}
+		for _, hashed := range hashedOutputs(wasm) {
+			for _, file := range []string{hashed, hashed + gzipExtension, hashed + brotliExtension, hashed + symbolsFileExtension} {
+				if _, err := os.Stat(file); err == nil {
+					files = append(files, file)
+				}
+			}
+		}
+	}
+	return files
+}
+
+// outputManifestPath returns the path of the output manifest in OutputDir
+func (w *TinyWasm) outputManifestPath() string {
+	return filepath.Join(w.Config.AppRootDir, w.Config.OutputDir, outputManifestName)
+}
+
+// outputManifestNames returns the names listed in the output manifest
+func (w *TinyWasm) outputManifestNames() []string {
+	data, err := os.ReadFile(w.outputManifestPath())
+	if err != nil {
+		return nil
+	}
+	var names []string
+	for _, line := range strings.Split(string(data), "\n") {
+		if name := strings.TrimSpace(line); name != "" && !slices.Contains(names, name) {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// currentOutputNames returns the output names in use: OutputName and the
+// OutputNamePerMode overrides
+func (w *TinyWasm) currentOutputNames() []string {
+	names := []string{w.Config.OutputName}
+	for _, mode := range []string{w.Config.BuildLargeSizeShortcut, w.Config.BuildMediumSizeShortcut, w.Config.BuildSmallSizeShortcut} {
+		if name := w.Config.OutputNamePerMode[mode]; name != "" && !slices.Contains(names, name) {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// recordOutputName adds the output name of mode (OutputName or its OutputNamePerMode
+// override) to the output manifest after a successful build. Previous names are kept
+// until CleanStaleOutputs.
+func (w *TinyWasm) recordOutputName(mode string) {
+	name := w.Config.OutputNamePerMode[mode]
+	if name == "" {
+		name = w.Config.OutputName
+	}
+
+	names := w.outputManifestNames()
+	if slices.Contains(names, name) {
+		return
+	}
+	names = append(names, name)
+
+	content := strings.Join(names, "\n") + "\n"
+	if err := os.WriteFile(w.outputManifestPath(), []byte(content), w.outputFilePerm()); err != nil {
+		w.Logger("Warning: could not update output manifest:", err)
+	}
+}
+
+// detectStaleOutputs logs the outputs left by a previous OutputName, if any
+func (w *TinyWasm) detectStaleOutputs() {
+	if stale := w.StaleOutputs(); len(stale) > 0 {
+		w.Logger("Found", len(stale), "outputs from a previous OutputName in", w.Config.OutputDir, "- call CleanStaleOutputs to remove them")
+	}
+}
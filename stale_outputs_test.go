@@ -0,0 +1,64 @@
+package tinywasm
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestCleanStaleOutputs(t *testing.T) {
+	tmp := t.TempDir()
+	outputDir := filepath.Join(tmp, "public")
+	if err := os.MkdirAll(outputDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	config := &Config{
+		AppRootDir:              tmp,
+		OutputDir:               "public",
+		OutputName:              "main",
+		DisableWasmExecJsOutput: true,
+		Logger:                  func(...any) {},
+	}
+	w := New(config)
+	w.recordOutputName(w.Value())
+
+	for _, name := range []string{"main.wasm", "main.wasm.gz", "main_v2.wasm", "app.wasm"} {
+		if err := os.WriteFile(filepath.Join(outputDir, name), []byte("x"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if stale := w.StaleOutputs(); len(stale) != 0 {
+		t.Errorf("no outputs should be stale before OutputName changes, got %v", stale)
+	}
+
+	// OutputName renamed between runs
+	config.OutputName = "app"
+	w = New(config)
+	w.recordOutputName(w.Value())
+
+	expected := []string{filepath.Join(outputDir, "main.wasm"), filepath.Join(outputDir, "main.wasm.gz")}
+	if stale := w.StaleOutputs(); !slices.Equal(stale, expected) {
+		t.Errorf("StaleOutputs() = %v, want %v", stale, expected)
+	}
+
+	if err := w.CleanStaleOutputs(); err != nil {
+		t.Fatalf("CleanStaleOutputs failed: %v", err)
+	}
+
+	for _, name := range []string{"main_v2.wasm", "app.wasm"} {
+		if _, err := os.Stat(filepath.Join(outputDir, name)); err != nil {
+			t.Errorf("%s should not be removed: %v", name, err)
+		}
+	}
+	for _, file := range expected {
+		if _, err := os.Stat(file); !os.IsNotExist(err) {
+			t.Errorf("%s should have been removed", file)
+		}
+	}
+	if names := w.outputManifestNames(); !slices.Equal(names, []string{"app"}) {
+		t.Errorf("manifest names = %v, want [app]", names)
+	}
+}
@@ -0,0 +1,114 @@
+package tinywasm
+
+import (
+	"os"
+	"strconv"
+	"strings"
+
+	. "github.com/cdvelop/tinystring"
+)
+
+// symbolsFileExtension is appended to the wasm output name for the symbols file
+const symbolsFileExtension = ".symbols"
+
+// wasmNameSubsectionFunctions is the "name" section subsection holding function names
+const wasmNameSubsectionFunctions = 1
+
+// WasmFunctionName maps a wasm function index to its symbol name
+type WasmFunctionName struct {
+	Index uint64
+	Name  string
+}
+
+// SymbolFilePath returns the symbols file written next to the active wasm output
+// eg: "web/public/main.wasm.symbols". Empty when Config.EmitSymbols is disabled.
+func (w *TinyWasm) SymbolFilePath() string {
+	if !w.Config.EmitSymbols || w.currentBuilder() == nil {
+		return ""
+	}
+	return w.outputPathFor(w.currentBuilder()) + symbolsFileExtension
+}
+
+// writeSymbolsFile extracts the function names of the wasm module at outputPath
+// and writes them to "<outputPath>.symbols", one "index<TAB>name" per line
+func writeSymbolsFile(outputPath string, perm os.FileMode) error {
+	data, err := os.ReadFile(outputPath)
+	if err != nil {
+		return err
+	}
+
+	names, err := parseWasmFunctionNames(data)
+	if err != nil {
+		return err
+	}
+	if len(names) == 0 {
+		return Err("wasm output", outputPath, "has no name section (built with -no-debug?)")
+	}
+
+	var sb strings.Builder
+	for _, fn := range names {
+		sb.WriteString(strconv.FormatUint(fn.Index, 10))
+		sb.WriteByte('\t')
+		sb.WriteString(fn.Name)
+		sb.WriteByte('\n')
+	}
+
+	return os.WriteFile(outputPath+symbolsFileExtension, []byte(sb.String()), perm)
+}
+
+// parseWasmFunctionNames returns the function names from the "name" custom section
+func parseWasmFunctionNames(data []byte) ([]WasmFunctionName, error) {
+	var names []WasmFunctionName
+
+	err := walkWasmSections(data, func(id byte, payload []byte) error {
+		if id != wasmSectionCustom {
+			return nil
+		}
+		r := &wasmReader{data: payload}
+		sectionName, err := r.name()
+		if err != nil || sectionName != "name" {
+			return err
+		}
+
+		for r.pos < len(r.data) {
+			subID, err := r.byte()
+			if err != nil {
+				return err
+			}
+			size, err := r.uleb()
+			if err != nil {
+				return err
+			}
+			content, err := r.bytes(int(size))
+			if err != nil {
+				return err
+			}
+			if subID != wasmNameSubsectionFunctions {
+				continue
+			}
+
+			sub := &wasmReader{data: content}
+			count, err := sub.uleb()
+			if err != nil {
+				return err
+			}
+			for i := uint64(0); i < count; i++ {
+				index, err := sub.uleb()
+				if err != nil {
+					return err
+				}
+				name, err := sub.name()
+				if err != nil {
+					return err
+				}
+				names = append(names, WasmFunctionName{Index: index, Name: name})
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return names, nil
+}
@@ -0,0 +1,79 @@
+package tinywasm
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+// buildTestWasmModuleWithNames returns buildTestWasmModule plus a "name" section
+// naming functions by index
+func buildTestWasmModuleWithNames(names ...string) []byte {
+	functions := []byte{byte(len(names))}
+	for i, name := range names {
+		functions = append(functions, byte(i))
+		functions = append(functions, wasmTestName(name)...)
+	}
+
+	payload := wasmTestName("name")
+	payload = append(payload, 0x00, 0x02, 0x01, 'm')            // module name subsection (ignored)
+	payload = append(payload, wasmTestSection(1, functions)...) // function names subsection
+	return append(buildTestWasmModule("run"), wasmTestSection(0, payload)...)
+}
+
+func TestParseWasmFunctionNames(t *testing.T) {
+	names, err := parseWasmFunctionNames(buildTestWasmModuleWithNames("runtime.alloc", "main.main"))
+	if err != nil {
+		t.Fatalf("parseWasmFunctionNames failed: %v", err)
+	}
+
+	expected := []WasmFunctionName{{0, "runtime.alloc"}, {1, "main.main"}}
+	if !slices.Equal(names, expected) {
+		t.Errorf("parseWasmFunctionNames() = %v, want %v", names, expected)
+	}
+}
+
+func TestEmitSymbols(t *testing.T) {
+	tmp := t.TempDir()
+	w := New(&Config{
+		AppRootDir:              tmp,
+		OutputDir:               "public",
+		DisableWasmExecJsOutput: true,
+		Logger:                  func(...any) {},
+		EmitSymbols:             true,
+	})
+
+	if !slices.Contains(w.UnobservedFiles(), "main.wasm.symbols") {
+		t.Errorf("UnobservedFiles() = %v, missing main.wasm.symbols", w.UnobservedFiles())
+	}
+	if slices.Contains(w.compilingArguments(w.Config.BuildSmallSizeShortcut), "-no-debug") {
+		t.Error("small mode should keep debug info when EmitSymbols is enabled")
+	}
+
+	// Simulate a finished TinyGo build
+	output := filepath.Join(tmp, "public", "main.wasm")
+	if err := os.MkdirAll(filepath.Dir(output), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(output, buildTestWasmModuleWithNames("runtime.alloc", "main.main"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.afterCompile(w.builderMedium, w.Config.BuildMediumSizeShortcut, nil); err != nil {
+		t.Fatalf("afterCompile failed: %v", err)
+	}
+
+	w.updateCurrentBuilder(w.Config.BuildMediumSizeShortcut)
+	symbols, err := os.ReadFile(w.SymbolFilePath())
+	if err != nil {
+		t.Fatalf("reading symbols file: %v", err)
+	}
+	if got, want := string(symbols), "0\truntime.alloc\n1\tmain.main\n"; got != want {
+		t.Errorf("symbols file = %q, want %q", got, want)
+	}
+
+	w.Config.EmitSymbols = false
+	if w.SymbolFilePath() != "" {
+		t.Error("SymbolFilePath should be empty when EmitSymbols is disabled")
+	}
+}
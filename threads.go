@@ -0,0 +1,94 @@
+package tinywasm
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	. "github.com/cdvelop/tinystring"
+)
+
+// goroutineHeavyThreshold is the number of `go` statements from which a
+// project is considered goroutine-heavy by UsesThreads
+const goroutineHeavyThreshold = 8
+
+// UsesThreads scans the Go sources under SourceDir for patterns suggesting the
+// app relies on parallel execution: explicit thread usage (runtime.GOMAXPROCS
+// calls, SharedArrayBuffer/Atomics access through syscall/js) or a goroutine-heavy
+// design (goroutineHeavyThreshold or more `go` statements).
+//
+// The check is heuristic. When it reports true a warning is logged: Go and TinyGo
+// wasm run goroutines on a single thread, and real browser threads need
+// SharedArrayBuffer, which is only available on cross-origin isolated pages
+// (COOP "same-origin" and COEP "require-corp" response headers).
+func (w *TinyWasm) UsesThreads() (bool, error) {
+	sourceDir := filepath.Join(w.Config.AppRootDir, w.Config.SourceDir)
+
+	var reasons []string
+	goStatements := 0
+	fset := token.NewFileSet()
+
+	err := filepath.Walk(sourceDir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			if path != sourceDir && (HasPrefix(info.Name(), ".") || info.Name() == "vendor") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if filepath.Ext(path) != ".go" || HasSuffix(path, "_test.go") {
+			return nil
+		}
+
+		file, err := parser.ParseFile(fset, path, nil, 0)
+		if err != nil {
+			return nil // unparsable files are reported by the compiler, not here
+		}
+
+		ast.Inspect(file, func(n ast.Node) bool {
+			switch node := n.(type) {
+			case *ast.GoStmt:
+				goStatements++
+			case *ast.SelectorExpr:
+				if pkg, ok := node.X.(*ast.Ident); ok && pkg.Name == "runtime" && node.Sel.Name == "GOMAXPROCS" {
+					reasons = append(reasons, "runtime.GOMAXPROCS in "+path)
+				}
+			case *ast.BasicLit:
+				if node.Kind == token.STRING {
+					if value, err := strconv.Unquote(node.Value); err == nil && (value == "SharedArrayBuffer" || value == "Atomics") {
+						reasons = append(reasons, value+" access in "+path)
+					}
+				}
+			}
+			return true
+		})
+		return nil
+	})
+	if err != nil {
+		if os.IsNotExist(err) {
+			return false, nil
+		}
+		return false, Err("scanning sources for thread usage:", err)
+	}
+
+	if goStatements >= goroutineHeavyThreshold {
+		reasons = append(reasons, strconv.Itoa(goStatements)+" goroutine launches")
+	}
+
+	if len(reasons) == 0 {
+		return false, nil
+	}
+
+	w.Logger("Warning: project appears to rely on threads (" + strings.Join(reasons, ", ") + ").")
+	w.Logger("Go/TinyGo wasm runs goroutines on a single thread; browser threads require SharedArrayBuffer,",
+		"which needs cross-origin isolation: serve pages with 'Cross-Origin-Opener-Policy: same-origin'",
+		"and 'Cross-Origin-Embedder-Policy: require-corp'.")
+
+	return true, nil
+}
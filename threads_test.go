@@ -0,0 +1,74 @@
+package tinywasm
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestUsesThreads(t *testing.T) {
+	tests := []struct {
+		name   string
+		source string
+		want   bool
+	}{
+		{
+			name:   "plain main",
+			source: "package main\n\nfunc main() { go work() }\n\nfunc work() {}\n",
+			want:   false,
+		},
+		{
+			name:   "shared array buffer",
+			source: "package main\n\nimport \"syscall/js\"\n\nfunc main() { js.Global().Get(\"SharedArrayBuffer\") }\n",
+			want:   true,
+		},
+		{
+			name:   "gomaxprocs",
+			source: "package main\n\nimport \"runtime\"\n\nfunc main() { runtime.GOMAXPROCS(4) }\n",
+			want:   true,
+		},
+		{
+			name:   "goroutine heavy",
+			source: "package main\n\nfunc main() {\n" + strings.Repeat("\tgo work()\n", goroutineHeavyThreshold) + "}\n\nfunc work() {}\n",
+			want:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmp := t.TempDir()
+			webDir := filepath.Join(tmp, "web")
+			if err := os.MkdirAll(webDir, 0755); err != nil {
+				t.Fatalf("creating dir: %v", err)
+			}
+			if err := os.WriteFile(filepath.Join(webDir, "main.go"), []byte(tt.source), 0644); err != nil {
+				t.Fatalf("writing main.go: %v", err)
+			}
+
+			var logs []string
+			w := New(&Config{
+				AppRootDir:              tmp,
+				SourceDir:               "web",
+				DisableWasmExecJsOutput: true,
+				Logger: func(message ...any) {
+					logs = append(logs, fmt.Sprint(message...))
+				},
+			})
+
+			got, err := w.UsesThreads()
+			if err != nil {
+				t.Fatalf("UsesThreads failed: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("UsesThreads() = %v, want %v", got, tt.want)
+			}
+
+			warned := strings.Contains(strings.Join(logs, "\n"), "Cross-Origin-Embedder-Policy")
+			if warned != tt.want {
+				t.Errorf("expected COOP/COEP warning logged = %v, logs: %v", tt.want, logs)
+			}
+		})
+	}
+}
@@ -0,0 +1,106 @@
+package tinywasm
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	. "github.com/cdvelop/tinystring"
+)
+
+// AvailableTinyGoTargets returns the targets supported by the installed TinyGo
+// (the output of "tinygo targets"), e.g. "wasm", "wasip1", "wasip2".
+// The result is cached per toolchain: it is refreshed when the tinygo executable
+// found in PATH changes or is reinstalled.
+func (w *TinyWasm) AvailableTinyGoTargets() ([]string, error) {
+	tinygoPath, err := exec.LookPath("tinygo")
+	if err != nil {
+		return nil, Errf("tinygo executable not found: %v", err)
+	}
+
+	cacheKey := toolchainCacheKey(tinygoPath)
+	if w.tinyGoTargets != nil && w.tinyGoTargetsKey == cacheKey {
+		return w.tinyGoTargets, nil
+	}
+
+	output, err := exec.Command(tinygoPath, "targets").Output()
+	if err != nil {
+		return nil, Errf("failed to list TinyGo targets: %v", err)
+	}
+
+	var targets []string
+	for _, line := range strings.Split(string(output), "\n") {
+		if target := strings.TrimSpace(line); target != "" {
+			targets = append(targets, target)
+		}
+	}
+
+	w.tinyGoTargets = targets
+	w.tinyGoTargetsKey = cacheKey
+
+	return targets, nil
+}
+
+// tinyGoTarget returns the -target of TinyGo builds (see Config.TinyGoTarget), with
+// custom .json targets resolved against AppRootDir since builds run from OutputDir
+func (w *TinyWasm) tinyGoTarget() string {
+	target := w.Config.TinyGoTarget
+	switch {
+	case target == "" && w.isReactor():
+		return "wasip1"
+	case target == "":
+		return "wasm"
+	case HasSuffix(target, ".json") && !filepath.IsAbs(target):
+		return filepath.Join(w.Config.AppRootDir, target)
+	}
+	return target
+}
+
+// isWasiTarget reports whether a TinyGo target runs on WASI (eg: "wasi", "wasip1",
+// "wasip2") instead of the browser runtime of wasm_exec.js
+func isWasiTarget(target string) bool {
+	return HasPrefix(target, "wasi")
+}
+
+// skipsWasmExecJs reports whether builds of mode run without wasm_exec.js: reactor
+// modules and TinyGo modes built for a WASI target
+func (w *TinyWasm) skipsWasmExecJs(mode string) bool {
+	return w.isReactor() || (w.requiresTinyGo(mode) && isWasiTarget(w.tinyGoTarget()))
+}
+
+// toolchainCacheKey identifies an installed executable for caching its output: its path
+// plus its modification time, so a reinstall invalidates the cache
+func toolchainCacheKey(executable string) string {
+	if info, err := os.Stat(executable); err == nil {
+		return executable + "@" + info.ModTime().String()
+	}
+	return executable
+}
+
+// ValidateTinyGoTarget checks that target is supported by the installed TinyGo.
+// Custom target definitions (a path ending in ".json", relative to AppRootDir)
+// are accepted when the file exists.
+func (w *TinyWasm) ValidateTinyGoTarget(target string) error {
+	if HasSuffix(target, ".json") {
+		targetPath := target
+		if !filepath.IsAbs(targetPath) {
+			targetPath = filepath.Join(w.Config.AppRootDir, targetPath)
+		}
+		if _, err := os.Stat(targetPath); err != nil {
+			return Errf("custom TinyGo target file not found: %s", targetPath)
+		}
+		return nil
+	}
+
+	targets, err := w.AvailableTinyGoTargets()
+	if err != nil {
+		return err
+	}
+
+	if !slices.Contains(targets, target) {
+		return Errf("unknown TinyGo target %q, available: %s", target, strings.Join(targets, ", "))
+	}
+	return nil
+}
@@ -0,0 +1,107 @@
+package tinywasm
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"testing"
+)
+
+// fakeTinyGo installs a shell script named tinygo at the front of PATH that
+// prints the given targets and counts its invocations in a file.
+func fakeTinyGo(t *testing.T, targets string) (countFile string) {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("fake tinygo script requires a POSIX shell")
+	}
+
+	binDir := t.TempDir()
+	countFile = filepath.Join(binDir, "calls")
+	script := "#!/bin/sh\necho x >> " + countFile + "\nprintf '" + targets + "'\n"
+	if err := os.WriteFile(filepath.Join(binDir, "tinygo"), []byte(script), 0755); err != nil {
+		t.Fatalf("writing fake tinygo: %v", err)
+	}
+	t.Setenv("PATH", binDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+	return countFile
+}
+
+func TestAvailableTinyGoTargets(t *testing.T) {
+	countFile := fakeTinyGo(t, "wasm\\nwasip1\\nwasip2\\n")
+
+	w := New(&Config{AppRootDir: t.TempDir(), Logger: func(...any) {}})
+
+	targets, err := w.AvailableTinyGoTargets()
+	if err != nil {
+		t.Fatalf("AvailableTinyGoTargets failed: %v", err)
+	}
+	if len(targets) != 3 || targets[0] != "wasm" || targets[2] != "wasip2" {
+		t.Fatalf("unexpected targets: %v", targets)
+	}
+
+	// Second call must be served from the cache
+	if _, err := w.AvailableTinyGoTargets(); err != nil {
+		t.Fatalf("AvailableTinyGoTargets failed: %v", err)
+	}
+	calls, _ := os.ReadFile(countFile)
+	if len(calls) != len("x\n") {
+		t.Errorf("expected tinygo to be invoked once, got %q", calls)
+	}
+
+	if err := w.ValidateTinyGoTarget("wasip1"); err != nil {
+		t.Errorf("expected wasip1 to be valid: %v", err)
+	}
+	if err := w.ValidateTinyGoTarget("wams"); err == nil {
+		t.Error("expected error for unknown target wams")
+	}
+}
+
+func TestValidateTinyGoTargetCustomJSON(t *testing.T) {
+	tmp := t.TempDir()
+	w := New(&Config{AppRootDir: tmp, Logger: func(...any) {}})
+
+	if err := w.ValidateTinyGoTarget("targets/custom.json"); err == nil {
+		t.Error("expected error for missing custom target file")
+	}
+
+	if err := os.MkdirAll(filepath.Join(tmp, "targets"), 0755); err != nil {
+		t.Fatalf("creating dir: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(tmp, "targets", "custom.json"), []byte(`{"inherits":["wasm"]}`), 0644); err != nil {
+		t.Fatalf("writing custom target: %v", err)
+	}
+	if err := w.ValidateTinyGoTarget("targets/custom.json"); err != nil {
+		t.Errorf("expected custom target to be valid: %v", err)
+	}
+}
+
+// TestTinyGoTarget verifies the configured target reaches the TinyGo builds and that
+// WASI targets skip wasm_exec.js
+func TestTinyGoTarget(t *testing.T) {
+	tmp := t.TempDir()
+	w := New(&Config{AppRootDir: tmp, Logger: func(...any) {}})
+	small := w.Config.BuildSmallSizeShortcut
+
+	if args := w.compilingArguments(small); !slices.Equal(args[:2], []string{"-target", "wasm"}) {
+		t.Errorf("default Small arguments = %v, want -target wasm", args)
+	}
+	if w.skipsWasmExecJs(small) {
+		t.Error("wasm target should use wasm_exec.js")
+	}
+
+	w.Config.TinyGoTarget = "wasi"
+	if args := w.compilingArguments(small); !slices.Equal(args[:2], []string{"-target", "wasi"}) {
+		t.Errorf("Small arguments = %v, want -target wasi", args)
+	}
+	if !w.skipsWasmExecJs(small) || w.skipsWasmExecJs(w.Config.BuildLargeSizeShortcut) {
+		t.Error("only the TinyGo modes of a WASI target should skip wasm_exec.js")
+	}
+	if _, useTinyGo := w.WasmProjectTinyGoJsUse(small); useTinyGo {
+		t.Error("WASI target should not use TinyGo's wasm_exec.js")
+	}
+
+	w.Config.TinyGoTarget = "targets/board.json"
+	if got := w.tinyGoTarget(); got != filepath.Join(tmp, "targets", "board.json") {
+		t.Errorf("custom target = %s, want it resolved against AppRootDir", got)
+	}
+}
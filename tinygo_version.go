@@ -0,0 +1,95 @@
+package tinywasm
+
+import (
+	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
+
+	. "github.com/cdvelop/tinystring"
+)
+
+// tinyGoVersionPattern matches the release in "tinygo version 0.39.0 linux/amd64 (...)"
+var tinyGoVersionPattern = regexp.MustCompile(`\b\d+\.\d+\.\d+\b`)
+
+// tinyGoFlagMinVersions are the TinyGo releases that introduced arguments passed by the
+// TinyGo modes
+var tinyGoFlagMinVersions = map[string]string{
+	"-panic=trap": "0.14.0",
+}
+
+// TinyGoVersion returns the release of the installed TinyGo, eg: "0.39.0". The result is
+// cached per toolchain like AvailableTinyGoTargets. Returns an error when tinygo is not
+// in PATH or its version can't be parsed.
+func (w *TinyWasm) TinyGoVersion() (string, error) {
+	tinygoPath, err := exec.LookPath("tinygo")
+	if err != nil {
+		return "", Err("TinyGo not found in PATH:", err)
+	}
+
+	cacheKey := toolchainCacheKey(tinygoPath)
+	if w.tinyGoVersion != "" && w.tinyGoVersionKey == cacheKey {
+		return w.tinyGoVersion, nil
+	}
+
+	output, err := exec.Command(tinygoPath, "version").Output()
+	if err != nil {
+		return "", Err("failed to get TinyGo version:", err)
+	}
+	version := tinyGoVersionPattern.FindString(string(output))
+	if version == "" {
+		return "", Err("unexpected tinygo version output:", strings.TrimSpace(string(output)))
+	}
+
+	w.tinyGoInstalled = true
+	w.tinyGoVersion = version
+	w.tinyGoVersionKey = cacheKey
+	return version, nil
+}
+
+// checkMinTinyGoVersion returns an error when the installed TinyGo is older than
+// Config.MinTinyGoVersion
+func (w *TinyWasm) checkMinTinyGoVersion() error {
+	if w.Config.MinTinyGoVersion == "" {
+		return nil
+	}
+	version, err := w.TinyGoVersion()
+	if err != nil {
+		return err
+	}
+	min := strings.TrimPrefix(w.Config.MinTinyGoVersion, "v")
+	if !versionAtLeast(version, min) {
+		return Err("TinyGo", version, "is installed but", min, "or later is required (MinTinyGoVersion), update it from https://tinygo.org/getting-started/install/")
+	}
+	return nil
+}
+
+// warnUnsupportedTinyGoFlags logs the arguments of mode the installed TinyGo is too old
+// for (see tinyGoFlagMinVersions)
+func (w *TinyWasm) warnUnsupportedTinyGoFlags(mode string) {
+	version, err := w.TinyGoVersion()
+	if err != nil {
+		return
+	}
+	for _, arg := range w.compilingArguments(mode) {
+		if min, ok := tinyGoFlagMinVersions[arg]; ok && !versionAtLeast(version, min) {
+			w.Logger("Warning: TinyGo", version, "does not support", arg, "- it needs", min, "or later")
+		}
+	}
+}
+
+// versionAtLeast reports whether the dotted release version is min or later
+func versionAtLeast(version, min string) bool {
+	have, want := strings.Split(version, "."), strings.Split(min, ".")
+	for i := range want {
+		var h int
+		if i < len(have) {
+			h, _ = strconv.Atoi(have[i])
+		}
+		w, _ := strconv.Atoi(want[i])
+		if h != w {
+			return h > w
+		}
+	}
+	return true
+}
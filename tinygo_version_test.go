@@ -0,0 +1,52 @@
+package tinywasm
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestTinyGoVersion(t *testing.T) {
+	t.Setenv("PATH", t.TempDir())
+	w := New(&Config{AppRootDir: t.TempDir(), Logger: func(...any) {}})
+	if version, err := w.TinyGoVersion(); err == nil || version != "" {
+		t.Errorf("TinyGoVersion() = %q, %v: want an error without tinygo in PATH", version, err)
+	}
+
+	fakeTinyGo(t, "tinygo version 0.12.0 linux/amd64 (using go version go1.25.2 and LLVM version 20.1.1)\\n")
+	version, err := w.TinyGoVersion()
+	if err != nil || version != "0.12.0" {
+		t.Fatalf("TinyGoVersion() = %q, %v: want 0.12.0", version, err)
+	}
+
+	var logs []string
+	w.Config.Logger = func(msg ...any) { logs = append(logs, fmt.Sprint(msg...)) }
+	w.warnUnsupportedTinyGoFlags(w.Config.BuildSmallSizeShortcut)
+	if len(logs) != 1 || !strings.Contains(logs[0], "-panic=trap") {
+		t.Errorf("logs = %v, want a -panic=trap warning for TinyGo 0.12.0", logs)
+	}
+
+	if !versionAtLeast("0.39.0", "0.14.0") || versionAtLeast("0.9.1", "0.14.0") {
+		t.Error("versionAtLeast compares numerically")
+	}
+}
+
+// TestMinTinyGoVersion verifies Change refuses a TinyGo mode with an older TinyGo
+func TestMinTinyGoVersion(t *testing.T) {
+	fakeTinyGo(t, "tinygo version 0.28.1 linux/amd64\\n")
+	w := New(&Config{AppRootDir: t.TempDir(), MinTinyGoVersion: "0.30.0", Logger: func(...any) {}})
+
+	progress := make(chan string, 1)
+	w.Change(w.Config.BuildSmallSizeShortcut, progress)
+	if msg := <-progress; !strings.Contains(msg, "0.30.0") {
+		t.Errorf("progress = %q, want the required version", msg)
+	}
+	if w.Value() != w.Config.BuildLargeSizeShortcut {
+		t.Errorf("mode = %s, the switch should be refused", w.Value())
+	}
+
+	w.Config.MinTinyGoVersion = "0.28.0"
+	if err := w.checkMinTinyGoVersion(); err != nil {
+		t.Errorf("checkMinTinyGoVersion() = %v, want nil for 0.28.1", err)
+	}
+}
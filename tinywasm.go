@@ -3,6 +3,10 @@ package tinywasm
 import (
 	"os"
 	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/cdvelop/gobuild"
 	. "github.com/cdvelop/tinystring"
@@ -22,20 +26,57 @@ type TinyWasm struct {
 	tinyGoCompiler  bool // Enable TinyGo compiler (default: false for faster development)
 	wasmProject     bool // Automatically detected based on file structure
 	tinyGoInstalled bool // Cached TinyGo installation status
+	noToolchain     bool // Neither go nor tinygo found in PATH at New
 
 	// NEW: Explicit mode tracking to fix Value() method
-	currentMode string // Track current mode explicitly ("L", "M", "S")
+	currentMode string       // Track current mode explicitly ("L", "M", "S")
+	modeMu      sync.RWMutex // guards currentMode and activeBuilder, read by builds off the caller's goroutine
 
 	mode_large_go_wasm_exec_cache      string // cache wasm_exec.js file content per mode large
 	mode_medium_tinygo_wasm_exec_cache string // cache wasm_exec.js file content per mode medium
 	mode_small_tinygo_wasm_exec_cache  string // cache wasm_exec.js file content per mode small
+
+	embeddedAssets map[string]bool // absolute paths of go:embed assets, nil until first lookup
+
+	tinyGoTargets    []string // cached output of "tinygo targets"
+	tinyGoTargetsKey string   // toolchain the cached targets belong to
+	tinyGoVersion    string   // cached semver of "tinygo version" (see TinyGoVersion)
+	tinyGoVersionKey string   // toolchain the cached version belongs to
+
+	ignore ignoreList // parsed .tinywasmignore, reloaded when the file changes
+
+	buildMu      sync.Mutex       // guards the build state fields below
+	activeBuilds int              // builds started and not yet finished (see IsBuilding)
+	outputSizes  map[string]int64 // wasm size of the last successful build per mode (see LastOutputSize)
+
+	lastBuildDuration time.Duration // duration of the last finished build, 0 while one runs (see LastBuildDuration)
+
+	buildStartTimes map[string]time.Time             // start of the latest build per mode
+	history         buildHistory                     // recent build results (see BuildHistory)
+	queue           buildQueue                       // coalesced NewFileEvent builds (see MaxPendingBuilds)
+	debounce        debouncedChanges                 // changes waiting for DebounceInterval to elapse
+	outputLocks     map[string]*sync.Mutex           // serializes the builds of each output path (see acquireBuilder)
+	asyncBuilds     map[*gobuild.GoBuild]*asyncBuild // builders compiling asynchronously (see newBuilder)
+
+	fingerprints        map[string]sourceFingerprint // source that triggered the last successful build per mode
+	pendingFingerprints map[string]sourceFingerprint // source that triggered the build in progress per mode
+
+	diagnostics []Diagnostic // compiler diagnostics of the last finished build (see LastDiagnostics)
+
+	bundler   string           // JavaScript bundler detected in AppRootDir (see DetectedBundler)
+	detection DetectionReport  // outcome of project detection in New (see DetectionReport)
+	entries   []*entryBuilders // builders of Config.ExtraEntryPoints
+
+	eventsMu  sync.Mutex                   // guards eventSubs
+	eventSubs map[chan BuildEvent]struct{} // Events subscribers
 }
 
 // Config holds configuration for WASM compilation
 type Config struct {
 
 	// AppRootDir specifies the application root directory (absolute).
-	// e.g., "/home/user/project". If empty, defaults to "." to preserve existing behavior.
+	// e.g., "/home/user/project". If empty, defaults to ".". New resolves it to an
+	// absolute path once, so output paths don't follow later working directory changes.
 	AppRootDir string
 
 	// SourceDir specifies the directory containing the Go source for the webclient (relative to AppRootDir).
@@ -60,12 +101,287 @@ type Config struct {
 	Callback           func(error)     // Optional callback for async compilation
 	CompilingArguments func() []string // Build arguments for compilation (e.g., ldflags)
 
+	// OnBuildSuccess is called after each successful NewFileEvent or RecompileMainWasm
+	// build, once the post-build steps ran and wasm_exec.js is written, with the absolute
+	// wasm output path and its size in bytes, eg: to trigger a browser reload. Never
+	// called for failed builds.
+	OnBuildSuccess func(outputPath string, size int64)
+
+	// OnBuildError is called with the mode shortcut and the error whenever a NewFileEvent,
+	// RecompileMainWasm or Change build fails, eg: to show "Small build failed" with the
+	// compiler diagnostics. The error is still returned (or passed to Callback) as usual.
+	OnBuildError func(mode string, err error)
+
+	// GcFlags and AsmFlags return compiler (-gcflags) and assembler (-asmflags) flags for
+	// the given mode, eg: GcFlags returning []string{"all=-N", "-l"} disables optimizations
+	// and inlining for debugging. Go-only: ignored for TinyGo modes.
+	GcFlags  func(mode string) []string
+	AsmFlags func(mode string) []string
+
+	// CodingBuildTags are the build tags of the Large (coding) mode, passed as -tags.
+	// nil means []string{"dev"} (the historical default); set an empty slice to build
+	// without tags, eg: when files are guarded by //go:build !dev.
+	CodingBuildTags []string
+
+	// BuildTagsLarge, BuildTagsMedium and BuildTagsSmall are the build tags of each mode,
+	// passed as -tags, eg: []string{"prod"} for Small to flip feature flags by mode.
+	// BuildTagsLarge replaces CodingBuildTags (and its "dev" default) when not empty.
+	BuildTagsLarge  []string
+	BuildTagsMedium []string
+	BuildTagsSmall  []string
+
+	// GoFlags is passed as GOFLAGS to the Go (Large) build, appended to any inherited
+	// GOFLAGS, eg: "-mod=vendor" for vendored projects. GOOS/GOARCH are unaffected.
+	// It must not contain -tags: build tags come from the mode arguments (see Validate).
+	GoFlags string
+
+	// LDFlags returns the -ldflags of every build, called before each one so the value can
+	// change between builds, eg: "-X main.Version=" plus the current git SHA. -ldflags values
+	// and bare -X values from CompilingArguments are merged into the same -ldflags (the
+	// compiler only keeps the last one). TinyGo supports only a subset of the linker flags
+	// (mainly -X): a warning is logged when used in a TinyGo mode.
+	LDFlags func() string
+
+	// ExtraEnv are "KEY=value" variables added to the environment of every build,
+	// eg: "CGO_ENABLED=0" or a custom "HOME" for CI sandboxes. A variable the builder
+	// already sets (TMPDIR, GOFLAGS...) is replaced rather than duplicated, except the
+	// GOOS/GOARCH of Go builds, which are fixed by the mode.
+	ExtraEnv []string
+
+	// LargeFastCompile builds the Large (Go) mode with -gcflags=all=-N -l: optimizations
+	// and inlining disabled for quicker rebuilds and easier debugging during active coding,
+	// at the cost of a slightly larger and slower wasm. Merged with GcFlags when both are set.
+	LargeFastCompile bool
+
+	// AllowNoToolchain accepts running without any compiler installed (neither go nor
+	// tinygo in PATH): detection and file generation keep working and builds are skipped
+	// with a log message. Otherwise such builds fail and Validate reports the missing
+	// toolchain with install instructions.
+	AllowNoToolchain bool
+
+	// RebuildOnDepChange recompiles the current mode when go.mod or go.sum in AppRootDir
+	// change, so the served wasm reflects dependency updates (default true). A Config
+	// literal, where false can't be told apart from unset, gets true from New as well:
+	// start from NewConfig or set it to false after New to disable it.
+	RebuildOnDepChange bool
+
+	// ForceRecompile disables the skipping of NewFileEvent builds for a source file whose
+	// modification time and size did not change since the last successful build of the
+	// mode (eg: duplicate write events from the watcher).
+	ForceRecompile bool
+
 	// DisableWasmExecJsOutput prevents automatic creation of wasm_exec.js file
 	// Useful when embedding wasm_exec.js content inline (e.g., Cloudflare Pages Advanced Mode)
 	DisableWasmExecJsOutput bool
 
+	// TempDir sets where intermediate build files go instead of the OS temp dir, eg: a
+	// dedicated volume in sandboxed CI, relative to AppRootDir unless absolute. Passed to
+	// the compilers as TMPDIR and GOTMPDIR and used by EstimateSize for its scratch output.
+	// Validate checks it exists and is writable.
+	TempDir string
+
+	// MaxWasmBytes fails the builds of the MaxWasmBytesModes whose wasm output is larger,
+	// eg: "wasm size 340KB exceeds limit 256KB", to catch dependency bloat in CI. The
+	// output is kept for inspection. 0 disables the limit.
+	MaxWasmBytes int64
+
+	// MaxWasmBytesModes are the mode shortcuts MaxWasmBytes applies to (default: Small)
+	MaxWasmBytesModes []string
+
+	// DefaultWasmTemplatePath replaces the embedded starter the default main file is
+	// generated from (see CreateDefaultWasmFileClientIfNotExist): a markdown file whose go
+	// code blocks are extracted, or a plain .go file. Relative to AppRootDir. Generation
+	// fails when the file can't be read.
+	DefaultWasmTemplatePath string
+
+	// EmitBuildStats writes build-stats.json in OutputDir after every successful build of
+	// the main program (mode, output, size, duration, compiler and timestamp, see
+	// BuildStats) for dashboards. The file is listed in UnobservedFiles.
+	EmitBuildStats bool
+
+	// DebounceInterval coalesces the events NewFileEvent receives within the interval,
+	// eg: format-on-save followed by the save itself: each event restarts the timer and a
+	// single build runs once it elapses, asynchronously (NewFileEvent returns nil, see
+	// OnBuildError for failures). 0 compiles on every event.
+	DebounceInterval time.Duration
+
+	// MaxPendingBuilds enables coalescing of file-event builds: while a build runs, new
+	// requests collapse into one pending build per mode (at most MaxPendingBuilds modes,
+	// the oldest pending request is dropped for a newer one) that runs when the current
+	// build ends. Prevents compile storms on bulk changes such as a git checkout. 0 builds
+	// on every event: a build of the same mode still running is cancelled and restarted,
+	// builds never overlap. Has no effect with Callback set, where builds always restart.
+	MaxPendingBuilds int
+
+	// HistorySize is the number of recent builds kept for BuildHistory (default 20)
+	HistorySize int
+
+	// TimeoutLarge, TimeoutMedium and TimeoutSmall limit each mode's compilation. Zero
+	// uses the defaults: 30s for the Go build, 60s for TinyGo debug and 120s for the
+	// optimized TinyGo build.
+	TimeoutLarge  time.Duration
+	TimeoutMedium time.Duration
+	TimeoutSmall  time.Duration
+
+	// DebugOutputSuffix is appended to OutputName for the Medium (debug) build only, eg:
+	// ".debug" produces main.debug.wasm so a debug build can coexist with the production
+	// main.wasm. OutputRelativePath and the generated JS follow it while Medium is active.
+	DebugOutputSuffix string
+
+	// ExtraEntryPoints are additional programs compiled next to the main one, each to its
+	// own output in OutputDir with the compiler of the active mode, eg: a web worker
+	// {InputFile: "worker.wasm.go", OutputName: "worker"}. A change to an entry's input
+	// file only rebuilds that entry; other .go changes rebuild the main program and then
+	// every entry, since they may share packages.
+	ExtraEntryPoints []EntryPoint
+
+	// OutputNamePerMode overrides OutputName for individual modes, keyed by mode shortcut,
+	// eg: {"L": "app-dev", "S": "app"} writes app-dev.wasm in Large and app.wasm in Small.
+	// Modes without an entry use OutputName (plus DebugOutputSuffix for Medium).
+	OutputNamePerMode map[string]string
+
+	// OutputFilePerm sets the permissions of the wasm output and of every file TinyWasm
+	// itself writes or renames during post-processing (symbols, compressed copies, hashed
+	// names). Zero keeps the defaults: the compiler's mode for the wasm output, 0644 otherwise.
+	OutputFilePerm os.FileMode
+
+	// EmitGzip writes a gzip copy "<output>.wasm.gz" next to the wasm after every successful
+	// build, for servers serving precompressed files. GzipLevel sets the compression level
+	// (compress/gzip levels, 0 means gzip.BestCompression).
+	EmitGzip  bool
+	GzipLevel int
+
+	// EmitBrotli writes a brotli copy "<output>.wasm.br" next to the wasm after every
+	// successful build (pure Go encoder, preferred by CDNs). BrotliQuality ranges from
+	// 1 to 11, 0 means 11. Compressed copies are deleted when a build fails.
+	EmitBrotli    bool
+	BrotliQuality int
+
+	// HashedOutputName renames the wasm after every successful build to include the
+	// first 8 hex digits of its SHA-256, eg: "main.1a2b3c4d.wasm", so it can be served
+	// with long cache lifetimes. The generated JS fetches the hashed name and previous
+	// hashed outputs are removed. See TinyWasm.HashedOutputName.
+	HashedOutputName bool
+
+	// EmitSymbols writes a "<output>.wasm.symbols" file (function index and name per line,
+	// taken from the wasm "name" section) after every TinyGo (Medium/Small) build, so crash
+	// reports with trapped function indexes can be symbolicated. Small builds keep their
+	// debug info when enabled.
+	EmitSymbols bool
+
+	// WasmABI selects the kind of module: "command" (default, main runs like a program)
+	// or "reactor" (WASI wasip1 library built with -buildmode=c-shared whose exports stay
+	// callable, see //go:wasmexport and //export). Reactor builds skip wasm_exec.js and
+	// JavascriptForInitializing returns reactor instantiation code instead. Use Validate
+	// to check toolchain support (TinyGo wasip1 target or go1.24+).
+	WasmABI string
+
+	// TinyGoTarget is the -target of the TinyGo (Medium/Small) builds: "wasm" by default
+	// ("wasip1" for reactor modules), a WASI target such as "wasi" or "wasip1" for
+	// server-side/edge runtimes, or a custom target .json file relative to AppRootDir.
+	// WASI targets don't use wasm_exec.js, so it is not written for those modes.
+	TinyGoTarget string
+
+	// MinTinyGoVersion is the oldest TinyGo release accepted for the TinyGo modes, eg:
+	// "0.30.0". Switching to a TinyGo mode with an older TinyGo installed is refused.
+	MinTinyGoVersion string
+
+	// AutoModeController optionally switches modes from the connected client count
+	// (see StartAutoMode), eg: Large while coding, Small while viewers are connected.
+	AutoModeController *AutoModeController
+
+	// DetectionMaxDepth limits how deep the .go file walk of project detection goes:
+	// directories more than this many levels below SourceDir (or below AppRootDir outside
+	// SourceDir) are skipped. 0 means unlimited. Bounds detection cost on large repos.
+	DetectionMaxDepth int
+
+	// DetectionIgnoreDirs extends the directory names skipped by the .go file walk of
+	// project detection (node_modules, vendor, .git and dist are always skipped), eg:
+	// []string{"testdata", "build"}. Matched by name at any depth.
+	DetectionIgnoreDirs []string
+
+	// DetectionOrder controls which sources project detection consults and in which order:
+	// DetectWasmExecJs ("wasm_exec_js"), DetectJsSignatures ("js_signatures") and DetectGoFiles
+	// ("go_files"). Empty means wasm_exec_js, js_signatures, go_files. Putting go_files before
+	// js_signatures lets the .go files win over a possibly stale committed wasm_exec.js.
+	DetectionOrder []string
+
+	// GoLandConfig makes InitProjectTooling also write the GoLand build constraints
+	// (GOOS=js GOARCH=wasm) to .idea/workspace.xml
+	GoLandConfig bool
+
+	// WasmExecJsSearchPaths lists extra locations checked first by GetWasmExecJsPathGo and
+	// GetWasmExecJsPathTinyGo, for portable or nonstandard toolchain layouts. Entries may be
+	// directories containing wasm_exec.js or direct .js file paths; a file is only used when
+	// its runtime signatures match the requested compiler.
+	WasmExecJsSearchPaths []string
+
+	// CustomWasmExecGoPath and CustomWasmExecTinyGoPath replace the embedded Go and TinyGo
+	// wasm_exec.js runtimes with project files (absolute or relative to AppRootDir), eg: a
+	// copy patched with extra imports or polyfills. The embedded runtime is used, with a
+	// warning, while the file cannot be read.
+	CustomWasmExecGoPath     string
+	CustomWasmExecTinyGoPath string
+
+	// PreferToolchainWasmExec generates wasm_exec.js from the runtime of the installed Go or
+	// TinyGo (see GetWasmExecJsPathGo) instead of the embedded copy, so it keeps matching
+	// the compiler across upgrades. Falls back to the embedded runtime when not found.
+	// Custom runtimes (CustomWasmExecGoPath) take precedence.
+	PreferToolchainWasmExec bool
+
+	// JSNamespace scopes the Go instance created by the generated JS footer under a global
+	// namespace object (must be a valid JavaScript identifier), eg: "MyApp" produces
+	// MyApp.go = new Go(). Avoids collisions when several wasm modules share one page.
+	JSNamespace string
+
+	// WasmInstantiation selects how the generated JS instantiates the module: "streaming"
+	// (default, WebAssembly.instantiateStreaming), "arraybuffer" (fetch the bytes, then
+	// WebAssembly.instantiate) for static hosts that don't serve .wasm as application/wasm,
+	// or "streaming-fallback" (streaming, retried as ArrayBuffer when it fails).
+	WasmInstantiation string
+
+	// OnWasmExecJsWritten is called after wasm_exec.js is (re)written, with its path and the
+	// mode it was generated for. Tools that bundle wasm_exec.js (e.g. AssetMin) can use it as a
+	// push-based signal. It is not called when the content on disk was already up to date.
+	OnWasmExecJsWritten func(path string, mode string)
+
+	// GenerateWorker enables GenerateWorkerBootstrap and writes its script as wasm_worker.js
+	// next to wasm_exec.js whenever wasm_exec.js is regenerated
+	GenerateWorker bool
+
+	// InjectIntoHTML is the path (relative to AppRootDir) of an existing page that should
+	// load the generated wasm_exec.js: the script tag is added once, without overwriting
+	// the page, whenever wasm_exec.js is written (see InjectLoaderIntoHTML)
+	InjectIntoHTML string
+
+	// ForceHTMLGeneration generates pages (eg: the InitProject index.html) even when a
+	// bundler setup such as vite.config.js is detected (see DetectedBundler)
+	ForceHTMLGeneration bool
+
+	// EmitSRI adds an integrity="sha384-..." attribute for wasm_exec.js to the generated
+	// index.html (see ExportZip, InitProject), computed from the JS shipped with it, and
+	// updates it in the project's pages whenever wasm_exec.js is regenerated. WasmExecJsSRI
+	// and WasmSRI expose the same values for hand-written pages and preload hints.
+	EmitSRI bool
+
+	// CrossOriginIsolated makes GenerateHostConfig emit the COOP "same-origin" and COEP
+	// "require-corp" headers needed for SharedArrayBuffer (see UsesThreads)
+	CrossOriginIsolated bool
+
+	// ServePrecompressed makes GenerateHostConfig serve precompressed .br/.gz variants
+	// of the outputs when present next to them
+	ServePrecompressed bool
+
+	// RequiredExports lists names the compiled module must export (e.g. //export'ed functions).
+	// After each build the wasm export section is checked and the build fails naming any missing entry.
+	RequiredExports []string
+
 	// LastOperationID tracks the last operation ID for progress reporting
 	lastOpID string
+
+	// boolDefaultsSet marks a config whose bool defaults (RebuildOnDepChange) were already
+	// applied, by NewConfig or a previous New
+	boolDefaultsSet bool
 }
 
 // NewConfig creates a TinyWasm Config with sensible defaults
@@ -80,6 +396,9 @@ func NewConfig() *Config {
 		BuildLargeSizeShortcut:  "L",
 		BuildMediumSizeShortcut: "M",
 		BuildSmallSizeShortcut:  "S",
+		CodingBuildTags:         []string{"dev"},
+		RebuildOnDepChange:      true,
+		boolDefaultsSet:         true,
 		Logger: func(message ...any) {
 			// Default logger: do nothing (silent operation)
 		},
@@ -105,6 +424,16 @@ func New(c *Config) *TinyWasm {
 		}
 	}
 
+	// Resolve the root once so a later os.Chdir of the host process can't move the outputs
+	if root, err := filepath.Abs(c.AppRootDir); err == nil {
+		if root != c.AppRootDir {
+			c.Logger("DEBUG: AppRootDir resolved to", root)
+		}
+		c.AppRootDir = root
+	} else {
+		c.Logger("Warning: could not resolve AppRootDir", c.AppRootDir, "to an absolute path:", err)
+	}
+
 	// Ensure shortcut defaults are set even when a partial config is passed
 	// Use NewConfig() as the authoritative source of defaults and copy any
 	// missing shortcut values from it.
@@ -124,6 +453,10 @@ func New(c *Config) *TinyWasm {
 	if c.OutputName == "" {
 		c.OutputName = defaults.OutputName
 	}
+	if !c.boolDefaultsSet {
+		c.RebuildOnDepChange = defaults.RebuildOnDepChange
+		c.boolDefaultsSet = true
+	}
 
 	w := &TinyWasm{
 		Config: c,
@@ -149,18 +482,107 @@ func New(c *Config) *TinyWasm {
 	// Initialize gobuild instance with WASM-specific configuration
 	w.builderWasmInit()
 
+	// Check that at least one compiler is installed
+	w.detectToolchains()
+
+	// Clean up temp outputs orphaned by a crashed previous build
+	w.recoverFromCrash()
+
+	// Report outputs left behind by a previous OutputName
+	w.detectStaleOutputs()
+
+	// Leave generated pages to an existing bundler setup
+	w.detectBundler()
+
+	// Load compile-trigger exclusions from .tinywasmignore
+	w.reloadIgnoreFileIfChanged()
+
 	// Perform one-time detection at the end
 	w.detectProjectConfiguration()
 
 	return w
 }
 
+// Reset clears the detected project state and runs detection again, eg: after the
+// embedding tool pointed AppRootDir (or SourceDir, OutputDir...) to another project.
+// Running and debounced builds are cancelled, awaiting their post-build steps. The
+// builders, wasm_exec.js caches, build fingerprints, bundler and .tinywasmignore are
+// reloaded, the output size, diagnostics, build history and queue of the previous
+// project are dropped and the mode goes back to Large unless the new project's
+// wasm_exec.js restores another one.
+func (w *TinyWasm) Reset() {
+	builders := []*gobuild.GoBuild{w.builderLarge, w.builderMedium, w.builderSmall}
+	for _, eb := range w.entries {
+		for _, b := range eb.builders {
+			builders = append(builders, b)
+		}
+	}
+	w.cancelBuilds(builders)
+
+	if root, err := filepath.Abs(w.Config.AppRootDir); err == nil {
+		w.Config.AppRootDir = root
+	}
+
+	w.wasmProject = false
+	w.tinyGoCompiler = false
+	w.modeMu.Lock()
+	w.currentMode = w.Config.BuildLargeSizeShortcut
+	w.modeMu.Unlock()
+	w.detection = DetectionReport{}
+	w.embeddedAssets = nil
+	w.ignore = ignoreList{}
+	w.ClearJavaScriptCache()
+
+	w.buildMu.Lock()
+	w.fingerprints = nil
+	w.pendingFingerprints = nil
+	w.outputSizes = nil
+	w.diagnostics = nil
+	w.history = buildHistory{}
+	w.queue.pending = nil
+	if w.debounce.timer != nil {
+		w.debounce.timer.Stop()
+	}
+	w.debounce.paths = nil
+	w.buildMu.Unlock()
+
+	w.builderWasmInit()
+	w.detectToolchains()
+	w.detectStaleOutputs()
+	w.detectBundler()
+	w.reloadIgnoreFileIfChanged()
+	w.detectProjectConfiguration()
+}
+
+// SetAppRootDir switches to the project at dir: AppRootDir is updated (resolved to an
+// absolute path) and Reset rebuilds the builders and detects the project again, so the
+// next build compiles the new project. Returns an error when dir is not a directory.
+func (w *TinyWasm) SetAppRootDir(dir string) error {
+	root, err := filepath.Abs(dir)
+	if err != nil {
+		return Err("resolving AppRootDir:", err)
+	}
+	info, err := os.Stat(root)
+	if err != nil {
+		return Err("AppRootDir:", err)
+	}
+	if !info.IsDir() {
+		return Err("AppRootDir", root, "is not a directory")
+	}
+
+	w.Config.AppRootDir = root
+	w.Reset()
+	return nil
+}
+
 // Name returns the name of the WASM project
 func (w *TinyWasm) Name() string {
 	return "TinyWasm"
 }
 
-// WasmProjectTinyGoJsUse returns dynamic state based on current configuration
+// WasmProjectTinyGoJsUse returns dynamic state based on current configuration: whether
+// this is a wasm project and whether mode (default: the active one) uses TinyGo's
+// wasm_exec.js, which is false for TinyGo WASI targets (see Config.TinyGoTarget)
 func (w *TinyWasm) WasmProjectTinyGoJsUse(mode ...string) (isWasmProject bool, useTinyGo bool) {
 	var currentMode string
 	if len(mode) > 0 {
@@ -169,7 +591,7 @@ func (w *TinyWasm) WasmProjectTinyGoJsUse(mode ...string) (isWasmProject bool, u
 		currentMode = w.Value()
 	}
 
-	useTinyGo = w.requiresTinyGo(currentMode)
+	useTinyGo = w.requiresTinyGo(currentMode) && !isWasiTarget(w.tinyGoTarget())
 
 	return w.wasmProject, useTinyGo
 }
@@ -183,6 +605,8 @@ func (w *TinyWasm) Label() string {
 
 // Value returns the current compiler mode shortcut (c, d, or p)
 func (w *TinyWasm) Value() string {
+	w.modeMu.RLock()
+	defer w.modeMu.RUnlock()
 	// Use explicit mode tracking instead of pointer comparison
 	if w.currentMode == "" {
 		return w.Config.BuildLargeSizeShortcut // Default to coding mode
@@ -190,25 +614,62 @@ func (w *TinyWasm) Value() string {
 	return w.currentMode
 }
 
-// detectProjectConfiguration performs one-time detection during initialization
+// Project detection sources for Config.DetectionOrder
+const (
+	DetectWasmExecJs   = "wasm_exec_js"  // restore the mode from the existing wasm_exec.js header
+	DetectJsSignatures = "js_signatures" // infer the compiler from the runtime signatures of the existing wasm_exec.js
+	DetectGoFiles      = "go_files"      // main input file or *.wasm.go files (wasm_exec.js is then regenerated)
+)
+
+// defaultDetectionOrder is the detection order used when Config.DetectionOrder is empty
+var defaultDetectionOrder = []string{DetectWasmExecJs, DetectJsSignatures, DetectGoFiles}
+
+// detectionOrder returns Config.DetectionOrder or the default order
+func (w *TinyWasm) detectionOrder() []string {
+	if len(w.Config.DetectionOrder) > 0 {
+		return w.Config.DetectionOrder
+	}
+	return defaultDetectionOrder
+}
+
+// detectProjectConfiguration performs one-time detection during initialization,
+// consulting the sources of Config.DetectionOrder in order. The wasm_exec.js header
+// only restores the mode; the first of the other sources that detects a project wins.
 func (w *TinyWasm) detectProjectConfiguration() {
-	// Priority 1: Check for existing wasm_exec.js (definitive source)
-	if w.detectFromExistingWasmExecJs() {
-		//w.Logger("DEBUG: WASM project detected from existing wasm_exec.js")
-		return
-	}
-
-	// Priority 2: Check for .go files (confirms WASM project)
-	if w.detectFromGoFiles() {
-		w.wasmProject = true
-		// If a project is detected from .go files, it means there's no wasm_exec.js,
-		// so we should create it.
-		if !w.Config.DisableWasmExecJsOutput {
-			w.wasmProjectWriteOrReplaceWasmExecJsOutput()
+	for _, source := range w.detectionOrder() {
+		switch source {
+		case DetectWasmExecJs:
+			w.detectModeFromWasmExecJsHeader()
+
+		case DetectJsSignatures:
+			if w.detectFromWasmExecJsSignatures() {
+				//w.Logger("DEBUG: WASM project detected from existing wasm_exec.js")
+				compiler := "go"
+				if w.tinyGoCompiler {
+					compiler = "tinygo"
+				}
+				w.detection = DetectionReport{IsWasmProject: true, Source: DetectionSourceWasmExecJs, Compiler: compiler, Mode: w.Value()}
+				return
+			}
+
+		case DetectGoFiles:
+			if w.detectFromGoFiles() {
+				w.wasmProject = true
+				w.detection = DetectionReport{IsWasmProject: true, Source: DetectionSourceGoFiles, Compiler: w.compilerCommand(w.Value()), Mode: w.Value()}
+				// The project is defined by its .go files: (re)create wasm_exec.js so a
+				// missing or stale file matches the current configuration.
+				if !w.Config.DisableWasmExecJsOutput {
+					w.wasmProjectWriteOrReplaceWasmExecJsOutput()
+				}
+				return
+			}
+
+		default:
+			w.Logger("Warning: unknown DetectionOrder source:", source)
 		}
-		return
 	}
 
+	w.detection = DetectionReport{Source: DetectionSourceNone, Compiler: w.compilerCommand(w.Value()), Mode: w.Value()}
 	w.Logger("No WASM project detected")
 }
 
@@ -223,6 +684,9 @@ func (w *TinyWasm) detectFromGoFiles() bool {
 		}
 
 		if info.IsDir() {
+			if w.skipDetectionDir(path, info.Name()) || w.beyondDetectionDepth(path) {
+				return filepath.SkipDir
+			}
 			return nil // Continue walking directories
 		}
 
@@ -257,3 +721,52 @@ func (w *TinyWasm) detectFromGoFiles() bool {
 
 	return wasmFilesFound
 }
+
+// defaultDetectionIgnoreDirs are the directory names never walked by detectFromGoFiles
+var defaultDetectionIgnoreDirs = []string{"node_modules", "vendor", ".git", "dist"}
+
+// skipDetectionDir reports whether detectFromGoFiles must skip dir because its name is in
+// defaultDetectionIgnoreDirs or Config.DetectionIgnoreDirs. AppRootDir, SourceDir and the
+// directories leading to it are always walked.
+func (w *TinyWasm) skipDetectionDir(dir, name string) bool {
+	if !slices.Contains(defaultDetectionIgnoreDirs, name) && !slices.Contains(w.Config.DetectionIgnoreDirs, name) {
+		return false
+	}
+	rel, err := filepath.Rel(w.Config.AppRootDir, dir)
+	if err != nil || rel == "." {
+		return false
+	}
+	sourceDir := filepath.Clean(w.Config.SourceDir)
+	return rel != sourceDir && !HasPrefix(sourceDir, rel+string(filepath.Separator))
+}
+
+// beyondDetectionDepth reports whether detectFromGoFiles must skip dir because of
+// Config.DetectionMaxDepth. Depth counts from SourceDir for directories inside it and
+// from AppRootDir elsewhere; the directories leading to SourceDir are always walked.
+func (w *TinyWasm) beyondDetectionDepth(dir string) bool {
+	if w.Config.DetectionMaxDepth <= 0 {
+		return false
+	}
+
+	rel, err := filepath.Rel(w.Config.AppRootDir, dir)
+	if err != nil || rel == "." {
+		return false
+	}
+	sourceDir := filepath.Clean(w.Config.SourceDir)
+
+	if fromSource, err := filepath.Rel(sourceDir, rel); err == nil && fromSource != ".." && !HasPrefix(fromSource, ".."+string(filepath.Separator)) {
+		return pathDepth(fromSource) > w.Config.DetectionMaxDepth
+	}
+	if HasPrefix(sourceDir, rel+string(filepath.Separator)) {
+		return false // ancestor of SourceDir
+	}
+	return pathDepth(rel) > w.Config.DetectionMaxDepth
+}
+
+// pathDepth returns the number of segments of a clean relative path ("." is 0)
+func pathDepth(rel string) int {
+	if rel == "." {
+		return 0
+	}
+	return len(strings.Split(rel, string(filepath.Separator)))
+}
@@ -0,0 +1,45 @@
+package tinywasm
+
+import (
+	"os/exec"
+
+	. "github.com/cdvelop/tinystring"
+)
+
+// noToolchainMessage explains how to install a compiler when neither go nor tinygo is available
+const noToolchainMessage = "no WebAssembly compiler found: neither go nor tinygo is in PATH. " +
+	"Install Go from https://go.dev/doc/install (Large mode) and/or TinyGo from " +
+	"https://tinygo.org/getting-started/install/ (Medium/Small modes)"
+
+// detectToolchains records whether any compiler usable by the modes is installed
+func (w *TinyWasm) detectToolchains() {
+	_, goErr := exec.LookPath("go")
+	_, tinyGoErr := exec.LookPath("tinygo")
+	w.noToolchain = goErr != nil && tinyGoErr != nil
+
+	if w.noToolchain {
+		if w.Config.AllowNoToolchain {
+			w.Logger("No Go or TinyGo toolchain found: running in detection/generation only mode, builds are skipped")
+		} else {
+			w.Logger("Warning:", noToolchainMessage)
+		}
+	}
+}
+
+// validateToolchain reports a missing toolchain unless Config.AllowNoToolchain is set
+func (w *TinyWasm) validateToolchain() error {
+	if w.noToolchain && !w.Config.AllowNoToolchain {
+		return Err(noToolchainMessage)
+	}
+	return nil
+}
+
+// skipBuildWithoutToolchain reports whether builds must be skipped because no compiler
+// is installed and Config.AllowNoToolchain accepts that, logging the skip
+func (w *TinyWasm) skipBuildWithoutToolchain() bool {
+	if w.noToolchain && w.Config.AllowNoToolchain {
+		w.Logger("Build skipped: no Go or TinyGo toolchain installed (AllowNoToolchain)")
+		return true
+	}
+	return false
+}
@@ -0,0 +1,54 @@
+package tinywasm
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestNoToolchain(t *testing.T) {
+	t.Setenv("PATH", t.TempDir()) // neither go nor tinygo available
+
+	tmp := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(tmp, "web"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(tmp, "web", "main.go"), []byte("package main\n\nfunc main() {}\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var logs []string
+	cfg := &Config{
+		AppRootDir:              tmp,
+		SourceDir:               "web",
+		OutputDir:               "web/public",
+		MainInputFile:           "main.go",
+		DisableWasmExecJsOutput: true,
+		Logger: func(msg ...any) {
+			logs = append(logs, fmt.Sprint(msg...))
+		},
+	}
+
+	w := New(cfg)
+	err := w.Validate()
+	if err == nil || !strings.Contains(err.Error(), "https://go.dev/doc/install") || !strings.Contains(err.Error(), "tinygo.org") {
+		t.Errorf("Validate() = %v, want error with install instructions for both compilers", err)
+	}
+	if err := w.RecompileMainWasm(); err == nil || !strings.Contains(err.Error(), "no WebAssembly compiler found") {
+		t.Errorf("RecompileMainWasm() = %v, want clear missing toolchain error", err)
+	}
+
+	cfg.AllowNoToolchain = true
+	w = New(cfg)
+	if err := w.Validate(); err != nil {
+		t.Errorf("Validate() with AllowNoToolchain = %v, want nil", err)
+	}
+	if err := w.RecompileMainWasm(); err != nil {
+		t.Errorf("RecompileMainWasm() with AllowNoToolchain = %v, want skipped build", err)
+	}
+	if !strings.Contains(strings.Join(logs, "\n"), "Build skipped") {
+		t.Error("expected a build skipped message")
+	}
+}
@@ -0,0 +1,138 @@
+package tinywasm
+
+import (
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	. "github.com/cdvelop/tinystring"
+)
+
+// gitignoreHeader marks the block of entries InitProjectTooling adds to .gitignore
+const gitignoreHeader = "# TinyWasm generated files"
+
+// InitProjectTooling scaffolds everything a fresh clone needs in one call: the default
+// main file when missing, VS Code settings, GoLand settings (Config.GoLandConfig) and
+// .gitignore entries for the generated files (see ManagedFiles). Every step is idempotent,
+// so it can be run again to refresh the configuration.
+func (w *TinyWasm) InitProjectTooling() error {
+	mainPath := filepath.Join(w.Config.AppRootDir, w.Config.SourceDir, w.Config.MainInputFile)
+	if _, err := os.Stat(mainPath); err != nil {
+		w.CreateDefaultWasmFileClientIfNotExist()
+	}
+
+	if !w.wasmProject {
+		if _, err := os.Stat(mainPath); err != nil {
+			return Err("not a WASM project: main file", mainPath, "not found")
+		}
+		w.wasmProject = true
+	}
+
+	w.VisualStudioCodeWasmEnvConfig()
+
+	if w.Config.GoLandConfig {
+		if err := w.GoLandWasmEnvConfig(); err != nil {
+			return err
+		}
+	}
+
+	return w.updateGitignore()
+}
+
+// GoLandWasmEnvConfig sets GOOS=js GOARCH=wasm as the build constraints of the GoLand
+// project (.idea/workspace.xml) so syscall/js resolves in the IDE. Existing workspace
+// settings are preserved and nothing is written when the constraints are already set.
+func (w *TinyWasm) GoLandWasmEnvConfig() error {
+	ideaDir := filepath.Join(w.Config.AppRootDir, ".idea")
+	workspacePath := filepath.Join(ideaDir, "workspace.xml")
+
+	component := `  <component name="GoBuildTags">
+    <option name="arch" value="wasm" />
+    <option name="os" value="js" />
+  </component>
+`
+
+	var content string
+	if data, err := os.ReadFile(workspacePath); err == nil {
+		content = string(data)
+		if strings.Contains(content, `<component name="GoBuildTags">`) {
+			return nil // keep the user's constraints
+		}
+		idx := strings.LastIndex(content, "</project>")
+		if idx < 0 {
+			return Err("unexpected GoLand workspace format:", workspacePath)
+		}
+		content = content[:idx] + component + content[idx:]
+	} else {
+		content = "<?xml version=\"1.0\" encoding=\"UTF-8\"?>\n<project version=\"4\">\n" + component + "</project>\n"
+	}
+
+	if err := os.MkdirAll(ideaDir, 0755); err != nil {
+		return Err("creating .idea directory:", err)
+	}
+	if err := os.WriteFile(workspacePath, []byte(content), 0644); err != nil {
+		return Err("writing GoLand workspace:", err)
+	}
+	return nil
+}
+
+// gitignorePath returns the path of the .gitignore updated by InitProjectTooling
+func (w *TinyWasm) gitignorePath() string {
+	return filepath.Join(w.Config.AppRootDir, ".gitignore")
+}
+
+// updateGitignore appends the generated files missing from AppRootDir/.gitignore. The
+// .gitignore itself and the index.html of InitProject are kept: they are meant to be
+// committed and edited.
+func (w *TinyWasm) updateGitignore() error {
+	gitignorePath := w.gitignorePath()
+
+	existing := ""
+	if data, err := os.ReadFile(gitignorePath); err == nil {
+		existing = string(data)
+	}
+
+	present := make(map[string]bool)
+	for _, line := range strings.Split(existing, "\n") {
+		present[strings.TrimSpace(line)] = true
+	}
+
+	var missing []string
+	for _, managed := range w.ManagedFiles() {
+		if managed == gitignorePath || managed == w.indexHTMLPath() {
+			continue
+		}
+		rel, err := filepath.Rel(w.Config.AppRootDir, managed)
+		if err != nil || HasPrefix(rel, "..") {
+			continue // outside the project
+		}
+		entry := "/" + path.Clean(filepath.ToSlash(rel))
+		if !present[entry] {
+			present[entry] = true
+			missing = append(missing, entry)
+		}
+	}
+
+	if len(missing) == 0 {
+		return nil
+	}
+
+	var sb strings.Builder
+	sb.WriteString(existing)
+	if existing != "" && !HasSuffix(existing, "\n") {
+		sb.WriteString("\n")
+	}
+	if !present[gitignoreHeader] {
+		if existing != "" {
+			sb.WriteString("\n")
+		}
+		sb.WriteString(gitignoreHeader + "\n")
+	}
+	sb.WriteString(strings.Join(missing, "\n") + "\n")
+
+	if err := os.WriteFile(gitignorePath, []byte(sb.String()), 0644); err != nil {
+		return Err("writing .gitignore:", err)
+	}
+	return nil
+}
@@ -0,0 +1,63 @@
+package tinywasm
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestInitProjectToolingIsIdempotent(t *testing.T) {
+	tmp := t.TempDir()
+	if err := os.WriteFile(filepath.Join(tmp, ".gitignore"), []byte("/bin"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	w := New(&Config{
+		AppRootDir:              tmp,
+		SourceDir:               "web",
+		OutputDir:               "web/public",
+		MainInputFile:           "main.go",
+		DisableWasmExecJsOutput: true,
+		GoLandConfig:            true,
+		Logger:                  func(...any) {},
+	})
+
+	if err := w.InitProjectTooling(); err != nil {
+		t.Fatalf("InitProjectTooling failed: %v", err)
+	}
+
+	for _, p := range []string{"web/main.go", ".vscode/settings.json", ".idea/workspace.xml"} {
+		if _, err := os.Stat(filepath.Join(tmp, p)); err != nil {
+			t.Errorf("expected %s to be generated: %v", p, err)
+		}
+	}
+
+	first, err := os.ReadFile(filepath.Join(tmp, ".gitignore"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"/bin\n", gitignoreHeader, "/web/public/main.wasm\n", "/web/public/main_temp*.wasm\n"} {
+		if !strings.Contains(string(first), want) {
+			t.Errorf(".gitignore missing %q:\n%s", want, first)
+		}
+	}
+	for _, kept := range []string{"/.gitignore", "/web/public/index.html"} {
+		if strings.Contains(string(first), kept+"\n") {
+			t.Errorf(".gitignore should not ignore %s:\n%s", kept, first)
+		}
+	}
+
+	// A second run must not change anything
+	if err := w.InitProjectTooling(); err != nil {
+		t.Fatalf("second InitProjectTooling failed: %v", err)
+	}
+	second, _ := os.ReadFile(filepath.Join(tmp, ".gitignore"))
+	if string(second) != string(first) {
+		t.Errorf(".gitignore changed on second run:\n%s\n---\n%s", first, second)
+	}
+	workspace, _ := os.ReadFile(filepath.Join(tmp, ".idea", "workspace.xml"))
+	if n := strings.Count(string(workspace), `<component name="GoBuildTags">`); n != 1 {
+		t.Errorf("workspace.xml has %d GoBuildTags components, want 1", n)
+	}
+}
@@ -0,0 +1,187 @@
+package tinywasm
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	. "github.com/cdvelop/tinystring"
+)
+
+// Validate checks the configuration against the installed toolchains and returns
+// the first problem found. It is not called by New (which never fails); call it
+// explicitly eg: at startup to surface misconfiguration early.
+func (w *TinyWasm) Validate() error {
+	if err := w.validateToolchain(); err != nil {
+		return err
+	}
+	if err := w.validateWasmABI(w.Value()); err != nil {
+		return err
+	}
+	if err := w.validateDetectionOrder(); err != nil {
+		return err
+	}
+	if err := w.validateOutputDirWritable(); err != nil {
+		return err
+	}
+	if err := w.validateTempDir(); err != nil {
+		return err
+	}
+	if err := w.validateGoFlags(); err != nil {
+		return err
+	}
+	if err := w.validateWasmInstantiation(); err != nil {
+		return err
+	}
+	if err := w.validateJSNamespace(); err != nil {
+		return err
+	}
+	if w.Config.TinyGoTarget != "" && w.requiresTinyGo(w.Value()) {
+		if err := w.ValidateTinyGoTarget(w.tinyGoTarget()); err != nil {
+			return err
+		}
+	}
+	if err := w.validateEntryPoints(); err != nil {
+		return err
+	}
+	return nil
+}
+
+// validateWasmInstantiation checks Config.WasmInstantiation is a supported value
+func (w *TinyWasm) validateWasmInstantiation() error {
+	switch w.Config.WasmInstantiation {
+	case "", WasmInstantiationStreaming, WasmInstantiationArrayBuffer, WasmInstantiationStreamingFallback:
+		return nil
+	}
+	return Err("invalid WasmInstantiation", Fmt("%q:", w.Config.WasmInstantiation), "must be", WasmInstantiationStreaming+",", WasmInstantiationArrayBuffer, "or", WasmInstantiationStreamingFallback)
+}
+
+// jsIdentifierPattern matches a plain JavaScript identifier, eg: "MyApp" or "$app_1"
+var jsIdentifierPattern = regexp.MustCompile(`^[A-Za-z_$][A-Za-z0-9_$]*$`)
+
+// validateJSNamespace checks Config.JSNamespace, when set, is a JavaScript identifier:
+// it is inserted as is in the generated JS (globalThis.<JSNamespace>)
+func (w *TinyWasm) validateJSNamespace() error {
+	if ns := w.Config.JSNamespace; ns != "" && !jsIdentifierPattern.MatchString(ns) {
+		return Err("invalid JSNamespace", Fmt("%q:", ns), "must be a JavaScript identifier, eg: MyApp")
+	}
+	return nil
+}
+
+// validateOutputDirWritable creates OutputDir if needed and writes a probe file,
+// so a read-only or misconfigured output directory is reported before a long build
+func (w *TinyWasm) validateOutputDirWritable() error {
+	outputDir := filepath.Join(w.Config.AppRootDir, w.Config.OutputDir)
+
+	if err := os.MkdirAll(outputDir, 0755); err != nil {
+		return Err("output directory not writable:", outputDir, err)
+	}
+
+	probe, err := os.CreateTemp(outputDir, ".tinywasm-probe-*")
+	if err != nil {
+		return Err("output directory not writable:", outputDir, err)
+	}
+	probe.Close()
+	os.Remove(probe.Name())
+
+	return nil
+}
+
+// validateTempDir checks that Config.TempDir, when set, is an existing writable directory.
+// Unlike OutputDir it is never created: a missing volume is a configuration error.
+func (w *TinyWasm) validateTempDir() error {
+	tempDir := w.tempDir()
+	if tempDir == "" {
+		return nil
+	}
+
+	info, err := os.Stat(tempDir)
+	if err != nil {
+		return Err("temp directory not found:", tempDir, err)
+	}
+	if !info.IsDir() {
+		return Err("temp directory is not a directory:", tempDir)
+	}
+
+	probe, err := os.CreateTemp(tempDir, ".tinywasm-probe-*")
+	if err != nil {
+		return Err("temp directory not writable:", tempDir, err)
+	}
+	probe.Close()
+	os.Remove(probe.Name())
+
+	return nil
+}
+
+// validateGoFlags checks Config.GoFlags is a GOFLAGS value (flags only) without -tags,
+// which would be silently overridden by the -tags passed on the command line
+func (w *TinyWasm) validateGoFlags() error {
+	for _, flag := range strings.Fields(w.Config.GoFlags) {
+		if !HasPrefix(flag, "-") {
+			return Errf("invalid GoFlags entry %q: GOFLAGS only accepts flags", flag)
+		}
+		name := strings.TrimLeft(flag, "-")
+		if name == "tags" || HasPrefix(name, "tags=") {
+			return Errf("GoFlags must not set %q: it conflicts with the build tags of the Large mode", flag)
+		}
+	}
+	return nil
+}
+
+// validateDetectionOrder rejects unknown Config.DetectionOrder sources
+func (w *TinyWasm) validateDetectionOrder() error {
+	for _, source := range w.Config.DetectionOrder {
+		if !slices.Contains(defaultDetectionOrder, source) {
+			return Errf("invalid DetectionOrder source %q: must be one of %s", source, strings.Join(defaultDetectionOrder, ", "))
+		}
+	}
+	return nil
+}
+
+// validateWasmABI checks Config.WasmABI and that the toolchain of mode can build it
+func (w *TinyWasm) validateWasmABI(mode string) error {
+	switch w.Config.WasmABI {
+	case "", WasmABICommand:
+		return nil
+	case WasmABIReactor:
+	default:
+		return Errf("invalid WasmABI %q: must be %q or %q", w.Config.WasmABI, WasmABICommand, WasmABIReactor)
+	}
+
+	if w.requiresTinyGo(mode) {
+		if err := w.ValidateTinyGoTarget(w.tinyGoTarget()); err != nil {
+			return Err("reactor modules need TinyGo wasip1 support:", err)
+		}
+		return nil
+	}
+
+	// Go supports wasip1 reactors (-buildmode=c-shared) since go1.24
+	version, err := toolchainVersion("go")
+	if err != nil {
+		return err
+	}
+	if minor, ok := goMinorVersion(version); ok && minor < 24 {
+		return Errf("reactor modules need go1.24 or later, found %s", version)
+	}
+	return nil
+}
+
+// goMinorVersion returns the minor number of a Go version eg: 24 for "go1.24.3"
+func goMinorVersion(version string) (int, bool) {
+	parts := strings.Split(strings.TrimPrefix(version, "go"), ".")
+	if len(parts) < 2 || parts[0] != "1" {
+		return 0, false
+	}
+	digits := parts[1]
+	if i := strings.IndexFunc(digits, func(r rune) bool { return r < '0' || r > '9' }); i >= 0 {
+		digits = digits[:i] // eg: "25rc1"
+	}
+	minor, err := strconv.Atoi(digits)
+	if err != nil {
+		return 0, false
+	}
+	return minor, true
+}
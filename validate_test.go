@@ -0,0 +1,128 @@
+package tinywasm
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestValidateOutputDirWritable(t *testing.T) {
+	tmp := t.TempDir()
+	w := New(&Config{
+		AppRootDir:              tmp,
+		OutputDir:               "web/public",
+		DisableWasmExecJsOutput: true,
+		Logger:                  func(...any) {},
+	})
+
+	if err := w.validateOutputDirWritable(); err != nil {
+		t.Fatalf("expected writable output dir: %v", err)
+	}
+	entries, err := os.ReadDir(filepath.Join(tmp, "web", "public"))
+	if err != nil {
+		t.Fatalf("output dir should have been created: %v", err)
+	}
+	if len(entries) != 0 {
+		t.Errorf("probe file should be removed, found %v", entries)
+	}
+
+	// A file where a directory is expected makes OutputDir unusable
+	if err := os.WriteFile(filepath.Join(tmp, "blocked"), []byte("x"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	w.Config.OutputDir = "blocked/public"
+	if err := w.Validate(); err == nil {
+		t.Error("expected Validate to report an unwritable output directory")
+	}
+}
+
+func TestTempDir(t *testing.T) {
+	tmp := t.TempDir()
+	scratch := filepath.Join(tmp, "scratch")
+	w := New(&Config{
+		AppRootDir:              tmp,
+		OutputDir:               "web/public",
+		TempDir:                 scratch,
+		DisableWasmExecJsOutput: true,
+		Logger:                  func(...any) {},
+	})
+
+	if err := w.validateTempDir(); err == nil {
+		t.Error("expected an error for a missing TempDir")
+	}
+
+	if err := os.Mkdir(scratch, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.validateTempDir(); err != nil {
+		t.Errorf("expected existing TempDir to be valid: %v", err)
+	}
+
+	env := w.builderConfig(w.Config.BuildSmallSizeShortcut).Env
+	if !slices.Contains(env, "TMPDIR="+scratch) || !slices.Contains(env, "GOTMPDIR="+scratch) {
+		t.Errorf("builder env = %v, want TMPDIR and GOTMPDIR set to %s", env, scratch)
+	}
+
+	// Relative to the project, not to the process working directory
+	w.Config.TempDir = "scratch"
+	if got := w.tempDir(); got != scratch {
+		t.Errorf("tempDir() = %s, want %s", got, scratch)
+	}
+}
+
+func TestGoFlags(t *testing.T) {
+	t.Setenv("GOFLAGS", "-modcacherw")
+	w := New(&Config{
+		AppRootDir:              t.TempDir(),
+		GoFlags:                 "-mod=vendor",
+		DisableWasmExecJsOutput: true,
+		Logger:                  func(...any) {},
+	})
+
+	env := w.builderConfig(w.Config.BuildLargeSizeShortcut).Env
+	expected := []string{"GOOS=js", "GOARCH=wasm", "GOFLAGS=-modcacherw -mod=vendor"}
+	if !slices.Equal(env, expected) {
+		t.Errorf("Large builder env = %v, want %v", env, expected)
+	}
+	if env := w.builderConfig(w.Config.BuildSmallSizeShortcut).Env; len(env) != 0 {
+		t.Errorf("TinyGo builder env = %v, GoFlags is Go-only", env)
+	}
+
+	if err := w.validateGoFlags(); err != nil {
+		t.Errorf("valid GoFlags rejected: %v", err)
+	}
+	for _, invalid := range []string{"-tags=prod", "-mod=vendor --tags=prod", "vendor"} {
+		w.Config.GoFlags = invalid
+		if err := w.validateGoFlags(); err == nil {
+			t.Errorf("expected GoFlags %q to be rejected", invalid)
+		}
+	}
+}
+
+func TestValidateJSNamespace(t *testing.T) {
+	w := New(&Config{
+		AppRootDir:              t.TempDir(),
+		DisableWasmExecJsOutput: true,
+		Logger:                  func(...any) {},
+	})
+
+	for _, valid := range []string{"", "MyApp", "$app", "_app1"} {
+		w.Config.JSNamespace = valid
+		if err := w.validateJSNamespace(); err != nil {
+			t.Errorf("JSNamespace %q rejected: %v", valid, err)
+		}
+	}
+	for _, invalid := range []string{"my-app", "1app", "app.go", "a; alert(1)", "my app"} {
+		w.Config.JSNamespace = invalid
+		if err := w.validateJSNamespace(); err == nil {
+			t.Errorf("JSNamespace %q should be rejected", invalid)
+		}
+	}
+
+	w.Config.JSNamespace = "my-app"
+	if err := w.Validate(); err == nil || !strings.Contains(err.Error(), "JSNamespace") {
+		t.Errorf("Validate() = %v, want an invalid JSNamespace error", err)
+	}
+}
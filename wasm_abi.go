@@ -0,0 +1,35 @@
+package tinywasm
+
+// Supported values of Config.WasmABI
+const (
+	WasmABICommand = "command" // default: main runs and the module exits (go.run style)
+	WasmABIReactor = "reactor" // WASI reactor: exported functions stay callable after _initialize
+)
+
+// isReactor reports whether Config.WasmABI selects reactor-style modules
+func (w *TinyWasm) isReactor() bool {
+	return w.Config.WasmABI == WasmABIReactor
+}
+
+// reactorJsFooter returns the initialization code for reactor modules. There is no Go
+// runtime to run: the module is instantiated with no-op WASI imports (every call returns
+// ENOSYS), _initialize is called and the exports are published as "<namespace>.exports"
+// (JSNamespace, "wasm" by default) for the page to call.
+func (h *TinyWasm) reactorJsFooter() string {
+	wasmFile := h.wasmFileName()
+
+	ns := h.Config.JSNamespace
+	if ns == "" {
+		ns = "wasm"
+	}
+
+	return `
+		globalThis.` + ns + ` = globalThis.` + ns + ` || {};
+		const wasi = new Proxy({}, { get: () => () => 52 }); // ENOSYS
+		` + h.instantiateJs(wasmFile, "{ wasi_snapshot_preview1: wasi }") + `.then((result) => {
+			const exports = result.instance.exports;
+			if (exports._initialize) exports._initialize();
+			` + ns + `.exports = exports;
+		});
+	`
+}
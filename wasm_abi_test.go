@@ -0,0 +1,72 @@
+package tinywasm
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestWasmABIReactor(t *testing.T) {
+	tmp := t.TempDir()
+	w := New(&Config{
+		AppRootDir:          tmp,
+		WasmExecJsOutputDir: "js",
+		WasmABI:             WasmABIReactor,
+		Logger:              func(...any) {},
+	})
+	w.wasmProject = true
+
+	small := w.compilingArguments(w.Config.BuildSmallSizeShortcut)
+	if !slices.Contains(small, "wasip1") || slices.Contains(small, "wasm") || !slices.Contains(small, "-buildmode=c-shared") {
+		t.Errorf("TinyGo reactor arguments = %v, want wasip1 target built as c-shared", small)
+	}
+	if large := w.compilingArguments(w.Config.BuildLargeSizeShortcut); !slices.Contains(large, "-buildmode=c-shared") {
+		t.Errorf("Go reactor arguments = %v, missing -buildmode=c-shared", large)
+	}
+
+	js, err := w.JavascriptForInitializing()
+	if err != nil {
+		t.Fatalf("JavascriptForInitializing failed: %v", err)
+	}
+	if strings.Contains(js, "new Go()") || strings.Contains(js, "runtime.wasmExit") {
+		t.Error("reactor init JS should not use the Go runtime")
+	}
+	if !strings.Contains(js, "exports._initialize()") || !strings.Contains(js, "wasm.exports = exports;") {
+		t.Errorf("reactor init JS should initialize and publish exports, got:\n%s", js)
+	}
+
+	w.wasmProjectWriteOrReplaceWasmExecJsOutput()
+	if _, err := os.Stat(filepath.Join(tmp, "js", "wasm_exec.js")); err == nil {
+		t.Error("wasm_exec.js should not be written for reactor modules")
+	}
+}
+
+func TestValidateWasmABI(t *testing.T) {
+	w := New(&Config{AppRootDir: t.TempDir(), WasmABI: "library", Logger: func(...any) {}})
+	if err := w.Validate(); err == nil {
+		t.Error("expected Validate to reject an unknown WasmABI")
+	}
+
+	w.Config.WasmABI = WasmABIReactor
+	if err := w.Validate(); err != nil {
+		t.Errorf("reactor should be valid for the Go mode with %s: %v", goVersionForTest(t), err)
+	}
+
+	for version, want := range map[string]int{"go1.24.3": 24, "go1.21": 21, "go1.25rc1": 25} {
+		if got, ok := goMinorVersion(version); !ok || got != want {
+			t.Errorf("goMinorVersion(%q) = %d, %v; want %d", version, got, ok, want)
+		}
+	}
+}
+
+// goVersionForTest returns the installed Go version for test messages
+func goVersionForTest(t *testing.T) string {
+	t.Helper()
+	v, err := toolchainVersion("go")
+	if err != nil {
+		t.Skipf("go toolchain unavailable: %v", err)
+	}
+	return v
+}
@@ -0,0 +1,56 @@
+package tinywasm
+
+import (
+	"os"
+	"regexp"
+	"slices"
+	"strings"
+
+	. "github.com/cdvelop/tinystring"
+)
+
+// wasmExecImportPattern matches the import names the runtime registers in the
+// importObject of a wasm_exec.js, eg: "runtime.wasmExit", "syscall/js.valueGet"
+var wasmExecImportPattern = regexp.MustCompile(`"(\$?(?:runtime|syscall/js|gojs)\.[\w.$]+)"`)
+
+// VerifyWasmExecCompatibility compares the embedded wasm_exec.js of the current mode's
+// compiler with the one shipped by the installed toolchain (see GetWasmExecJsPathGo and
+// GetWasmExecJsPathTinyGo). It returns false with the missing names when the toolchain's
+// runtime has signatures or imports the embedded file lacks: modules built by that
+// toolchain would then fail to instantiate with the generated wasm_exec.js.
+func (w *TinyWasm) VerifyWasmExecCompatibility() (bool, error) {
+	tinyGo := w.requiresTinyGo(w.Value())
+
+	compiler, embedded, signatures := "go", embeddedWasmExecGo, wasm_execGoSignatures()
+	livePath, err := w.GetWasmExecJsPathGo()
+	if tinyGo {
+		compiler, embedded, signatures = "tinygo", embeddedWasmExecTinyGo, wasm_execTinyGoSignatures()
+		livePath, err = w.GetWasmExecJsPathTinyGo()
+	}
+	if err != nil {
+		return false, Err("locating the installed wasm_exec.js:", err)
+	}
+
+	live, err := os.ReadFile(livePath)
+	if err != nil {
+		return false, Err("reading the installed wasm_exec.js:", err)
+	}
+
+	wanted := signatures
+	for _, match := range wasmExecImportPattern.FindAllStringSubmatch(string(live), -1) {
+		if !slices.Contains(wanted, match[1]) {
+			wanted = append(wanted, match[1])
+		}
+	}
+
+	var missing []string
+	for _, name := range wanted {
+		if strings.Contains(string(live), name) && !strings.Contains(string(embedded), name) {
+			missing = append(missing, name)
+		}
+	}
+	if len(missing) > 0 {
+		return false, Err("embedded", compiler, "wasm_exec.js is older than", livePath, "missing:", strings.Join(missing, ", "))
+	}
+	return true, nil
+}
@@ -0,0 +1,47 @@
+package tinywasm
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestVerifyWasmExecCompatibility(t *testing.T) {
+	tmp := t.TempDir()
+	w := New(&Config{
+		AppRootDir:              tmp,
+		DisableWasmExecJsOutput: true,
+		Logger:                  func(...any) {},
+	})
+
+	// A toolchain runtime identical to the embedded one
+	same := filepath.Join(tmp, "same")
+	if err := os.MkdirAll(same, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(same, "wasm_exec.js"), embeddedWasmExecGo, 0644); err != nil {
+		t.Fatal(err)
+	}
+	w.Config.WasmExecJsSearchPaths = []string{same}
+
+	if ok, err := w.VerifyWasmExecCompatibility(); !ok || err != nil {
+		t.Errorf("VerifyWasmExecCompatibility() = %v, %v; want compatible", ok, err)
+	}
+
+	// A newer toolchain registering an import the embedded runtime lacks
+	newer := filepath.Join(tmp, "newer")
+	if err := os.MkdirAll(newer, 0755); err != nil {
+		t.Fatal(err)
+	}
+	content := strings.Replace(string(embeddedWasmExecGo), `"runtime.wasmExit"`, `"runtime.wasmExit": () => {}, "runtime.newImport"`, 1)
+	if err := os.WriteFile(filepath.Join(newer, "wasm_exec.js"), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	w.Config.WasmExecJsSearchPaths = []string{newer}
+
+	ok, err := w.VerifyWasmExecCompatibility()
+	if ok || err == nil || !strings.Contains(err.Error(), "runtime.newImport") {
+		t.Errorf("VerifyWasmExecCompatibility() = %v, %v; want incompatible naming runtime.newImport", ok, err)
+	}
+}
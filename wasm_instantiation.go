@@ -0,0 +1,31 @@
+package tinywasm
+
+// Supported values of Config.WasmInstantiation
+const (
+	WasmInstantiationStreaming   = "streaming"   // default: WebAssembly.instantiateStreaming, needs the application/wasm MIME type
+	WasmInstantiationArrayBuffer = "arraybuffer" // download the bytes first, works whatever MIME type the server sends
+
+	// WasmInstantiationStreamingFallback streams and, when that fails with a TypeError
+	// (wrong MIME type, or no instantiateStreaming support), downloads the bytes again
+	WasmInstantiationStreamingFallback = "streaming-fallback"
+)
+
+// instantiateJs returns the JS expression instantiating the wasm file with importObject,
+// a promise resolving to {module, instance}, in the form selected by Config.WasmInstantiation
+func (w *TinyWasm) instantiateJs(wasmFile, importObject string) string {
+	streaming := `WebAssembly.instantiateStreaming(fetch("` + wasmFile + `"), ` + importObject + `)`
+	arrayBuffer := `fetch("` + wasmFile + `").then(function (response) { return response.arrayBuffer(); }).then(function (bytes) { return WebAssembly.instantiate(bytes, ` + importObject + `); })`
+
+	switch w.Config.WasmInstantiation {
+	case WasmInstantiationArrayBuffer:
+		return arrayBuffer
+	case WasmInstantiationStreamingFallback:
+		return `(typeof WebAssembly.instantiateStreaming === "function" ? ` + streaming + `.catch(function (err) {
+			if (!(err instanceof TypeError)) throw err;
+			console.warn("wasm streaming failed, retrying with ArrayBuffer:", err.message);
+			return ` + arrayBuffer + `;
+		}) : ` + arrayBuffer + `)`
+	default:
+		return streaming
+	}
+}
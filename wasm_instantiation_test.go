@@ -0,0 +1,52 @@
+package tinywasm
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestWasmInstantiation(t *testing.T) {
+	w := New(&Config{
+		AppRootDir:              t.TempDir(),
+		DisableWasmExecJsOutput: true,
+		Logger:                  func(...any) {},
+	})
+
+	if footer := w.defaultJsFooter(); !strings.Contains(footer, `WebAssembly.instantiateStreaming(fetch("main.wasm"), go.importObject)`) {
+		t.Errorf("default footer should stream:\n%s", footer)
+	}
+
+	w.Config.WasmInstantiation = WasmInstantiationArrayBuffer
+	footer := w.defaultJsFooter()
+	if strings.Contains(footer, "instantiateStreaming") {
+		t.Errorf("arraybuffer footer should not stream:\n%s", footer)
+	}
+	for _, expected := range []string{`fetch("main.wasm")`, "response.arrayBuffer()", "WebAssembly.instantiate(bytes, go.importObject)"} {
+		if !strings.Contains(footer, expected) {
+			t.Errorf("arraybuffer footer missing %q:\n%s", expected, footer)
+		}
+	}
+	if err := w.validateWasmInstantiation(); err != nil {
+		t.Errorf("valid value rejected: %v", err)
+	}
+
+	w.Config.WasmInstantiation = WasmInstantiationStreamingFallback
+	footer = w.defaultJsFooter()
+	for _, expected := range []string{
+		`WebAssembly.instantiateStreaming(fetch("main.wasm"), go.importObject).catch(`,
+		"err instanceof TypeError",
+		"WebAssembly.instantiate(bytes, go.importObject)",
+	} {
+		if !strings.Contains(footer, expected) {
+			t.Errorf("streaming-fallback footer missing %q:\n%s", expected, footer)
+		}
+	}
+	if err := w.validateWasmInstantiation(); err != nil {
+		t.Errorf("valid value rejected: %v", err)
+	}
+
+	w.Config.WasmInstantiation = "eager"
+	if err := w.validateWasmInstantiation(); err == nil || !strings.Contains(err.Error(), "eager") {
+		t.Errorf("validateWasmInstantiation() = %v, want invalid value error", err)
+	}
+}
@@ -0,0 +1,253 @@
+package tinywasm
+
+import (
+	"os"
+	"strings"
+
+	. "github.com/cdvelop/tinystring"
+)
+
+// WasmModuleInfo describes the imports and exports declared by a compiled wasm module
+type WasmModuleInfo struct {
+	Imports []string // "module.name" entries from the import section eg: "gojs.runtime.wasmExit"
+	Exports []string // export names from the export section eg: "run", "mem"
+}
+
+// HasExport reports whether the module exports the given name
+func (m *WasmModuleInfo) HasExport(name string) bool {
+	for _, e := range m.Exports {
+		if e == name {
+			return true
+		}
+	}
+	return false
+}
+
+// wasm section ids used by the parser
+const (
+	wasmSectionCustom = 0
+	wasmSectionImport = 2
+	wasmSectionExport = 7
+)
+
+// ReadWasmModuleInfo parses the import and export sections of a wasm binary file
+func ReadWasmModuleInfo(filePath string) (*WasmModuleInfo, error) {
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+	return parseWasmModuleInfo(data)
+}
+
+// parseWasmModuleInfo parses the import and export sections of a wasm binary
+func parseWasmModuleInfo(data []byte) (*WasmModuleInfo, error) {
+	info := &WasmModuleInfo{}
+
+	err := walkWasmSections(data, func(id byte, payload []byte) error {
+		r := &wasmReader{data: payload}
+		switch id {
+		case wasmSectionImport:
+			return r.readImports(info)
+		case wasmSectionExport:
+			return r.readExports(info)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return info, nil
+}
+
+// walkWasmSections validates the wasm header and calls fn for every section
+func walkWasmSections(data []byte, fn func(id byte, payload []byte) error) error {
+	if len(data) < 8 || string(data[:4]) != "\x00asm" {
+		return Err("invalid wasm module: missing magic header")
+	}
+
+	r := &wasmReader{data: data, pos: 8}
+	for r.pos < len(r.data) {
+		id, err := r.byte()
+		if err != nil {
+			return err
+		}
+		size, err := r.uleb()
+		if err != nil {
+			return err
+		}
+		payload, err := r.bytes(int(size))
+		if err != nil {
+			return err
+		}
+		if err := fn(id, payload); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// wasmReader decodes the primitive encodings of the wasm binary format
+type wasmReader struct {
+	data []byte
+	pos  int
+}
+
+func (r *wasmReader) byte() (byte, error) {
+	if r.pos >= len(r.data) {
+		return 0, Err("invalid wasm module: unexpected end of data")
+	}
+	b := r.data[r.pos]
+	r.pos++
+	return b, nil
+}
+
+func (r *wasmReader) bytes(n int) ([]byte, error) {
+	if n < 0 || r.pos+n > len(r.data) {
+		return nil, Err("invalid wasm module: unexpected end of data")
+	}
+	b := r.data[r.pos : r.pos+n]
+	r.pos += n
+	return b, nil
+}
+
+// uleb decodes an unsigned LEB128 integer
+func (r *wasmReader) uleb() (uint64, error) {
+	var result uint64
+	var shift uint
+	for {
+		b, err := r.byte()
+		if err != nil {
+			return 0, err
+		}
+		result |= uint64(b&0x7f) << shift
+		if b&0x80 == 0 {
+			return result, nil
+		}
+		shift += 7
+		if shift >= 64 {
+			return 0, Err("invalid wasm module: LEB128 overflow")
+		}
+	}
+}
+
+func (r *wasmReader) name() (string, error) {
+	n, err := r.uleb()
+	if err != nil {
+		return "", err
+	}
+	b, err := r.bytes(int(n))
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
+// limits skips a limits entry (flags, min and optional max)
+func (r *wasmReader) limits() error {
+	flags, err := r.byte()
+	if err != nil {
+		return err
+	}
+	if _, err := r.uleb(); err != nil {
+		return err
+	}
+	if flags&0x01 != 0 {
+		if _, err := r.uleb(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *wasmReader) readImports(info *WasmModuleInfo) error {
+	count, err := r.uleb()
+	if err != nil {
+		return err
+	}
+	for i := uint64(0); i < count; i++ {
+		module, err := r.name()
+		if err != nil {
+			return err
+		}
+		field, err := r.name()
+		if err != nil {
+			return err
+		}
+		kind, err := r.byte()
+		if err != nil {
+			return err
+		}
+
+		switch kind {
+		case 0x00: // func: type index
+			_, err = r.uleb()
+		case 0x01: // table: reftype + limits
+			if _, err = r.byte(); err == nil {
+				err = r.limits()
+			}
+		case 0x02: // memory: limits
+			err = r.limits()
+		case 0x03: // global: valtype + mutability
+			_, err = r.bytes(2)
+		case 0x04: // tag: attribute + type index
+			if _, err = r.byte(); err == nil {
+				_, err = r.uleb()
+			}
+		default:
+			return Err("invalid wasm module: unknown import kind", int(kind))
+		}
+		if err != nil {
+			return err
+		}
+
+		info.Imports = append(info.Imports, module+"."+field)
+	}
+	return nil
+}
+
+func (r *wasmReader) readExports(info *WasmModuleInfo) error {
+	count, err := r.uleb()
+	if err != nil {
+		return err
+	}
+	for i := uint64(0); i < count; i++ {
+		name, err := r.name()
+		if err != nil {
+			return err
+		}
+		// kind byte + index
+		if _, err := r.byte(); err != nil {
+			return err
+		}
+		if _, err := r.uleb(); err != nil {
+			return err
+		}
+		info.Exports = append(info.Exports, name)
+	}
+	return nil
+}
+
+// verifyRequiredExports fails when the compiled module lacks any of Config.RequiredExports
+func (w *TinyWasm) verifyRequiredExports(outputPath string) error {
+	if len(w.Config.RequiredExports) == 0 {
+		return nil
+	}
+
+	info, err := ReadWasmModuleInfo(outputPath)
+	if err != nil {
+		return Err("reading wasm exports:", err)
+	}
+
+	var missing []string
+	for _, name := range w.Config.RequiredExports {
+		if !info.HasExport(name) {
+			missing = append(missing, name)
+		}
+	}
+
+	if len(missing) > 0 {
+		return Err("wasm output", outputPath, "is missing required exports:", strings.Join(missing, ", "))
+	}
+	return nil
+}
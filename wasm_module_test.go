@@ -0,0 +1,81 @@
+package tinywasm
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// wasmTestSection encodes a wasm section with the given id and payload
+func wasmTestSection(id byte, payload []byte) []byte {
+	return append([]byte{id, byte(len(payload))}, payload...)
+}
+
+// wasmTestName encodes a length-prefixed wasm name
+func wasmTestName(s string) []byte {
+	return append([]byte{byte(len(s))}, s...)
+}
+
+// buildTestWasmModule returns a minimal module importing gojs.runtime.wasmExit
+// and exporting the given function names.
+func buildTestWasmModule(exports ...string) []byte {
+	module := []byte("\x00asm\x01\x00\x00\x00")
+
+	imports := []byte{1}
+	imports = append(imports, wasmTestName("gojs")...)
+	imports = append(imports, wasmTestName("runtime.wasmExit")...)
+	imports = append(imports, 0x00, 0x00) // func, type index 0
+	module = append(module, wasmTestSection(2, imports)...)
+
+	exportSection := []byte{byte(len(exports))}
+	for i, name := range exports {
+		exportSection = append(exportSection, wasmTestName(name)...)
+		exportSection = append(exportSection, 0x00, byte(i)) // func, index
+	}
+	module = append(module, wasmTestSection(7, exportSection)...)
+
+	return module
+}
+
+func TestParseWasmModuleInfo(t *testing.T) {
+	info, err := parseWasmModuleInfo(buildTestWasmModule("run", "resume"))
+	if err != nil {
+		t.Fatalf("parseWasmModuleInfo failed: %v", err)
+	}
+
+	if len(info.Imports) != 1 || info.Imports[0] != "gojs.runtime.wasmExit" {
+		t.Errorf("unexpected imports: %v", info.Imports)
+	}
+	if !info.HasExport("run") || !info.HasExport("resume") {
+		t.Errorf("unexpected exports: %v", info.Exports)
+	}
+
+	if _, err := parseWasmModuleInfo([]byte("not wasm")); err == nil {
+		t.Error("expected error for invalid module")
+	}
+}
+
+func TestVerifyRequiredExports(t *testing.T) {
+	tmp := t.TempDir()
+	outputPath := filepath.Join(tmp, "main.wasm")
+	if err := os.WriteFile(outputPath, buildTestWasmModule("run", "add"), 0644); err != nil {
+		t.Fatalf("writing test module: %v", err)
+	}
+
+	w := New(&Config{AppRootDir: tmp, Logger: func(...any) {}})
+
+	w.Config.RequiredExports = []string{"run", "add"}
+	if err := w.verifyRequiredExports(outputPath); err != nil {
+		t.Errorf("expected all exports present, got: %v", err)
+	}
+
+	w.Config.RequiredExports = []string{"run", "multiply", "divide"}
+	err := w.verifyRequiredExports(outputPath)
+	if err == nil {
+		t.Fatal("expected error for missing exports")
+	}
+	if !strings.Contains(err.Error(), "multiply, divide") {
+		t.Errorf("error should name the missing exports, got: %v", err)
+	}
+}
@@ -0,0 +1,92 @@
+package tinywasm
+
+import (
+	"os"
+	"path/filepath"
+
+	. "github.com/cdvelop/tinystring"
+)
+
+// workerFileName is the file written next to wasm_exec.js when Config.GenerateWorker is set
+const workerFileName = "wasm_worker.js"
+
+// GenerateWorkerBootstrap returns a Web Worker script that runs the wasm module off the
+// main thread: the wasm_exec.js runtime followed by code that instantiates the module
+// inside the worker and bridges messages with the page. Requires Config.GenerateWorker.
+//
+// Bridge: messages posted to the worker are delivered to self.onGoMessage(data), which
+// the Go side defines through syscall/js (messages arriving before it exists are queued
+// and flushed once main has started); Go answers with self.goPostMessage(data). The worker
+// posts {type: "ready"} once the module runs and {type: "error", message} on failure.
+func (w *TinyWasm) GenerateWorkerBootstrap() (string, error) {
+	if !w.Config.GenerateWorker {
+		return "", Errf("worker bootstrap disabled, set Config.GenerateWorker")
+	}
+	if w.isReactor() {
+		return "", Errf("worker bootstrap is not supported for %s modules", WasmABIReactor)
+	}
+	if w.currentBuilder() == nil {
+		return "", Errf("activeBuilder not initialized")
+	}
+	return w.JavascriptForInitializing(wasmExecJsHeader(w.Value()), w.workerJsFooter())
+}
+
+// WorkerOutputPath returns the path of the worker script written when
+// Config.GenerateWorker is set (next to wasm_exec.js)
+func (w *TinyWasm) WorkerOutputPath() string {
+	return filepath.Join(filepath.Dir(w.WasmExecJsOutputPath()), workerFileName)
+}
+
+// workerJsFooter returns the worker initialization code used by GenerateWorkerBootstrap
+func (w *TinyWasm) workerJsFooter() string {
+	wasmFile := w.wasmFileName()
+
+	return `
+		(function () {
+			const pending = [];
+
+			self.goPostMessage = function (data) { self.postMessage(data); };
+
+			self.onmessage = function (event) {
+				if (typeof self.onGoMessage === "function") {
+					self.onGoMessage(event.data);
+				} else {
+					pending.push(event.data);
+				}
+			};
+
+			function fail(err) {
+				self.postMessage({ type: "error", message: err && err.message ? err.message : String(err) });
+			}
+
+			const go = new Go();
+			` + w.instantiateJs(wasmFile, "go.importObject") + `.then(function (result) {
+				go.run(result.instance).catch(fail);
+				// main has run up to its first blocking point: handlers it registered exist now
+				while (pending.length > 0 && typeof self.onGoMessage === "function") {
+					self.onGoMessage(pending.shift());
+				}
+				self.postMessage({ type: "ready" });
+			}).catch(fail);
+		})();
+	`
+}
+
+// writeWorkerBootstrap writes the worker script to WorkerOutputPath, skipping the
+// write when the file on disk is already up to date
+func (w *TinyWasm) writeWorkerBootstrap() {
+	content, err := w.GenerateWorkerBootstrap()
+	if err != nil {
+		w.Logger("Failed to generate worker bootstrap:", err)
+		return
+	}
+
+	outputPath := w.WorkerOutputPath()
+	if existing, err := os.ReadFile(outputPath); err == nil && string(existing) == content {
+		return
+	}
+
+	if err := os.WriteFile(outputPath, []byte(content), 0644); err != nil {
+		w.Logger("Failed to write worker bootstrap:", err)
+	}
+}
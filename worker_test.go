@@ -0,0 +1,53 @@
+package tinywasm
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestGenerateWorkerBootstrap(t *testing.T) {
+	tmp := t.TempDir()
+	w := New(&Config{
+		AppRootDir:          tmp,
+		WasmExecJsOutputDir: "js",
+		Logger:              func(...any) {},
+	})
+	w.wasmProject = true
+
+	if _, err := w.GenerateWorkerBootstrap(); err == nil {
+		t.Error("expected error while Config.GenerateWorker is disabled")
+	}
+
+	w.Config.GenerateWorker = true
+	js, err := w.GenerateWorkerBootstrap()
+	if err != nil {
+		t.Fatalf("GenerateWorkerBootstrap failed: %v", err)
+	}
+	for _, expected := range []string{"const go = new Go();", "self.onGoMessage", "self.goPostMessage", `type: "ready"`} {
+		if !strings.Contains(js, expected) {
+			t.Errorf("worker bootstrap missing %q", expected)
+		}
+	}
+	if strings.Contains(js, "document") {
+		t.Error("worker bootstrap must not reference document")
+	}
+
+	w.wasmProjectWriteOrReplaceWasmExecJsOutput()
+	written, err := os.ReadFile(filepath.Join(tmp, "js", workerFileName))
+	if err != nil {
+		t.Fatalf("expected %s to be written: %v", workerFileName, err)
+	}
+	if string(written) != js {
+		t.Error("written worker script differs from GenerateWorkerBootstrap")
+	}
+
+	pageJs, err := os.ReadFile(w.WasmExecJsOutputPath())
+	if err != nil {
+		t.Fatalf("reading wasm_exec.js: %v", err)
+	}
+	if strings.Contains(string(pageJs), "onGoMessage") {
+		t.Error("wasm_exec.js should keep the page footer, not the worker one")
+	}
+}